@@ -0,0 +1,632 @@
+package sqlinsert
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Postgres array and composite (row) literals, e.g., {a,"b c",NULL} and (1,"b c",), bound as text by fields with
+// the array or composite tag option and decoded by ScanArray and ScanComposite.
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// encodeArray returns v, a slice or array, as a Postgres array literal, or nil for a nil slice or pointer.
+// Slices of slices are multidimensional arrays.
+func encodeArray(v interface{}) (interface{}, error) {
+	rv, ok := indirect(v)
+	if !ok {
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf(`no Postgres array for Go type %s`, rv.Type())
+	}
+	var b strings.Builder
+	if err := writeArray(&b, rv); err != nil {
+		return nil, err
+	}
+	return b.String(), nil
+}
+
+// encodeComposite returns v, a struct, as a Postgres composite literal of its exported fields, in order, or nil
+// for a nil pointer.
+func encodeComposite(v interface{}) (interface{}, error) {
+	rv, ok := indirect(v)
+	if !ok {
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`no Postgres composite for Go type %s`, rv.Type())
+	}
+	return compositeText(rv)
+}
+
+// indirect returns the value v points to, or false for nil, a nil pointer, or a nil slice.
+func indirect(v interface{}) (reflect.Value, bool) {
+	if v == nil {
+		return reflect.Value{}, false
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.Value{}, false
+	}
+	return rv, true
+}
+
+// writeArray writes the elements of rv, a slice or array, as a Postgres array literal. Elements that are
+// themselves slices (other than []byte) are written as the sub-arrays of a multidimensional array.
+func writeArray(b *strings.Builder, rv reflect.Value) error {
+	b.WriteString(`{`)
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			b.WriteString(`,`)
+		}
+		ev := rv.Index(i)
+		if sub, ok := subArray(ev); ok {
+			if err := writeArray(b, sub); err != nil {
+				return err
+			}
+			continue
+		}
+		text, ok, err := elementText(ev.Interface())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			b.WriteString(`NULL`)
+			continue
+		}
+		if text == `` || strings.EqualFold(text, `NULL`) || strings.ContainsAny(text, "{},\"\\ \t\n\r\v\f") {
+			text = quoteElement(text)
+		}
+		b.WriteString(text)
+	}
+	b.WriteString(`}`)
+	return nil
+}
+
+// subArray returns the slice or array that ev holds, if ev is the sub-array of a multidimensional array.
+func subArray(ev reflect.Value) (reflect.Value, bool) {
+	for (ev.Kind() == reflect.Pointer || ev.Kind() == reflect.Interface) && !ev.IsNil() &&
+		!ev.Type().Implements(valuerType) {
+		ev = ev.Elem()
+	}
+	if ev.Type().Implements(valuerType) {
+		return ev, false
+	}
+	switch ev.Kind() {
+	case reflect.Slice, reflect.Array:
+		return ev, ev.Type().Elem().Kind() != reflect.Uint8
+	}
+	return ev, false
+}
+
+// compositeText returns rv, a struct, as a Postgres composite literal of its exported fields. NULL is empty.
+func compositeText(rv reflect.Value) (string, error) {
+	var (
+		b     strings.Builder
+		t     = rv.Type()
+		first = true
+	)
+	b.WriteString(`(`)
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != `` {
+			continue // unexported
+		}
+		if !first {
+			b.WriteString(`,`)
+		}
+		first = false
+		text, ok, err := elementText(rv.Field(i).Interface())
+		if err != nil {
+			return ``, fmt.Errorf(`field %s: %w`, t.Field(i).Name, err)
+		}
+		if !ok {
+			continue
+		}
+		if text == `` || strings.ContainsAny(text, "(),\"\\ \t\n\r\v\f") {
+			text = quoteElement(text)
+		}
+		b.WriteString(text)
+	}
+	b.WriteString(`)`)
+	return b.String(), nil
+}
+
+// elementText returns v as the text of an array element or composite field, or false for NULL. driver.Valuer
+// values are converted first.
+func elementText(v interface{}) (string, bool, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return ``, false, nil
+		}
+		value, err := valuer.Value()
+		if err != nil {
+			return ``, false, err
+		}
+		v = value
+	}
+	if v == nil {
+		return ``, false, nil
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.Format(`2006-01-02 15:04:05.999999999-07:00`), true, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return ``, false, nil
+		}
+		return elementText(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String(), true, nil
+	case reflect.Bool:
+		if rv.Bool() {
+			return `t`, true, nil
+		}
+		return `f`, true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		switch {
+		case math.IsInf(f, 1):
+			return `Infinity`, true, nil
+		case math.IsInf(f, -1):
+			return `-Infinity`, true, nil
+		}
+		return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()), true, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return ``, false, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return `\x` + hex.EncodeToString(b), true, nil // bytea
+		}
+		var b strings.Builder
+		if err := writeArray(&b, rv); err != nil {
+			return ``, false, err
+		}
+		return b.String(), true, nil
+	case reflect.Struct:
+		text, err := compositeText(rv)
+		return text, err == nil, err
+	}
+	return ``, false, fmt.Errorf(`no Postgres array element for Go type %T`, v)
+}
+
+// quoteElement double-quotes s, escaping double quotes and backslashes with backslashes.
+func quoteElement(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// ScanArray returns a sql.Scanner that decodes a Postgres array literal into dest, a pointer to a slice, e.g., to
+// read back a field inserted with the array tag option:
+//
+//	err := row.Scan(&rec.ID, sqlinsert.ScanArray(&rec.Tags))
+//
+// NULL is a nil slice.
+func ScanArray(dest interface{}) sql.Scanner {
+	return literalScanner{dest: dest, parse: ParseArray}
+}
+
+// ScanComposite returns a sql.Scanner that decodes a Postgres composite literal into dest, a pointer to a struct,
+// e.g., to read back a field inserted with the composite tag option. NULL is the zero value.
+func ScanComposite(dest interface{}) sql.Scanner {
+	return literalScanner{dest: dest, parse: ParseComposite}
+}
+
+// ParseArray decodes the Postgres array literal s into dest, a pointer to a slice or array (or to a pointer to
+// one) of strings, numbers, booleans, time.Time, []byte, structs (composites), sql.Scanner types, or slices
+// (sub-arrays), or pointers to any of them. NULL elements are zero values.
+func ParseArray(s string, dest interface{}) error {
+	dv, err := destValue(dest)
+	if err != nil {
+		return err
+	}
+	if k := indirectKind(dv.Type()); k != reflect.Slice && k != reflect.Array {
+		return fmt.Errorf(`sqlinsert: cannot parse Postgres array into %s`, dv.Type())
+	}
+	if err = decodeText(dv, s); err != nil {
+		return fmt.Errorf(`sqlinsert: Postgres array: %w`, err)
+	}
+	return nil
+}
+
+// ParseComposite decodes the Postgres composite literal s into dest, a pointer to a struct (or to a struct
+// pointer), one field per exported field, in order. NULL fields are zero values.
+func ParseComposite(s string, dest interface{}) error {
+	dv, err := destValue(dest)
+	if err != nil {
+		return err
+	}
+	if indirectKind(dv.Type()) != reflect.Struct {
+		return fmt.Errorf(`sqlinsert: cannot parse Postgres composite into %s`, dv.Type())
+	}
+	if err = decodeText(dv, s); err != nil {
+		return fmt.Errorf(`sqlinsert: Postgres composite: %w`, err)
+	}
+	return nil
+}
+
+// literalScanner scans a Postgres array or composite literal into dest.
+type literalScanner struct {
+	dest  interface{}
+	parse func(s string, dest interface{}) error
+}
+
+// Scan implements sql.Scanner.
+func (s literalScanner) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		dv, err := destValue(s.dest)
+		if err != nil {
+			return err
+		}
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	case string:
+		return s.parse(src, s.dest)
+	case []byte:
+		return s.parse(string(src), s.dest)
+	}
+	return fmt.Errorf(`sqlinsert: cannot scan %T as a Postgres literal`, src)
+}
+
+// destValue returns the value that dest, a non-nil pointer, points to.
+func destValue(dest interface{}) (reflect.Value, error) {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return reflect.Value{}, fmt.Errorf(`sqlinsert: destination %T is not a non-nil pointer`, dest)
+	}
+	return dv.Elem(), nil
+}
+
+// indirectKind returns the kind of the type that t points to, through any number of pointers.
+func indirectKind(t reflect.Type) reflect.Kind {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+// decodeText sets dv to the value of s, the text of an array element or composite field.
+func decodeText(dv reflect.Value, s string) error {
+	if scanner, ok := dv.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(s)
+	}
+	if dv.Type() == timeType {
+		t, err := parseTime(s)
+		if err != nil {
+			return err
+		}
+		dv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch dv.Kind() {
+	case reflect.Pointer:
+		ev := reflect.New(dv.Type().Elem())
+		if err := decodeText(ev.Elem(), s); err != nil {
+			return err
+		}
+		dv.Set(ev)
+		return nil
+	case reflect.Interface:
+		if dv.NumMethod() > 0 {
+			break
+		}
+		dv.Set(reflect.ValueOf(s))
+		return nil
+	case reflect.String:
+		dv.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		dv.SetBool(b)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, dv.Type().Bits())
+		dv.SetInt(i)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, dv.Type().Bits())
+		dv.SetUint(u)
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dv.Type().Bits())
+		dv.SetFloat(f)
+		return err
+	case reflect.Slice, reflect.Array:
+		if dv.Type().Elem().Kind() == reflect.Uint8 {
+			if !strings.HasPrefix(s, `\x`) {
+				return fmt.Errorf(`expected bytea hex format, got %q`, s)
+			}
+			b, err := hex.DecodeString(s[2:])
+			if err != nil {
+				return err
+			}
+			if dv.Kind() == reflect.Array {
+				reflect.Copy(dv, reflect.ValueOf(b))
+			} else {
+				dv.SetBytes(b)
+			}
+			return nil
+		}
+		p := literalParser{s: s}
+		elems, err := p.array()
+		if err != nil {
+			return err
+		}
+		return decodeArray(dv, elems)
+	case reflect.Struct:
+		p := literalParser{s: s}
+		fields, err := p.composite()
+		if err != nil {
+			return err
+		}
+		return decodeComposite(dv, fields)
+	}
+	return fmt.Errorf(`cannot decode %q into Go type %s`, s, dv.Type())
+}
+
+// decodeArray sets dv, a slice or array, to the parsed elements of an array literal.
+func decodeArray(dv reflect.Value, elems []interface{}) error {
+	if dv.Kind() == reflect.Slice {
+		dv.Set(reflect.MakeSlice(dv.Type(), len(elems), len(elems)))
+	} else if len(elems) > dv.Len() {
+		return fmt.Errorf(`%d elements do not fit in Go type %s`, len(elems), dv.Type())
+	}
+	for i, elem := range elems {
+		if err := decodeElement(dv.Index(i), elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeElement sets ev to a parsed element: nil (NULL), a string, or the elements of a sub-array.
+func decodeElement(ev reflect.Value, elem interface{}) error {
+	switch elem := elem.(type) {
+	case nil:
+		ev.Set(reflect.Zero(ev.Type()))
+		return nil
+	case string:
+		return decodeText(ev, elem)
+	}
+	for ev.Kind() == reflect.Pointer {
+		if ev.IsNil() {
+			ev.Set(reflect.New(ev.Type().Elem()))
+		}
+		ev = ev.Elem()
+	}
+	if ev.Kind() != reflect.Slice && ev.Kind() != reflect.Array {
+		return fmt.Errorf(`cannot decode sub-array into Go type %s`, ev.Type())
+	}
+	return decodeArray(ev, elem.([]interface{}))
+}
+
+// decodeComposite sets the exported fields of dv, a struct, to the parsed fields of a composite literal, in order.
+func decodeComposite(dv reflect.Value, fields []interface{}) error {
+	t := dv.Type()
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != `` {
+			continue // unexported
+		}
+		if n == len(fields) {
+			return fmt.Errorf(`%d fields do not fill Go type %s`, len(fields), t)
+		}
+		if err := decodeElement(dv.Field(i), fields[n]); err != nil {
+			return fmt.Errorf(`field %s: %w`, t.Field(i).Name, err)
+		}
+		n++
+	}
+	if n < len(fields) {
+		return fmt.Errorf(`%d fields do not fit in Go type %s`, len(fields), t)
+	}
+	return nil
+}
+
+// parseTime parses a Postgres timestamp, timestamptz, or date.
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range []string{
+		`2006-01-02 15:04:05.999999999-07:00`,
+		`2006-01-02 15:04:05.999999999-07`,
+		`2006-01-02 15:04:05.999999999`,
+		time.RFC3339Nano,
+		`2006-01-02`,
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`cannot parse %q as a timestamp`, s)
+}
+
+// literalParser parses Postgres array and composite literals into elements that are nil (NULL), strings, or, in
+// arrays, the elements of sub-arrays.
+type literalParser struct {
+	s string
+	i int
+}
+
+var errUnterminated = errors.New(`unterminated literal`)
+
+// array parses an array literal, ignoring dimension decoration, e.g., [0:1]={a,b}.
+func (p *literalParser) array() ([]interface{}, error) {
+	if strings.HasPrefix(p.s, `[`) {
+		if i := strings.Index(p.s, `=`); i >= 0 {
+			p.i = i + 1
+		}
+	}
+	elems, err := p.subArray()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(p.s[p.i:]) != `` {
+		return nil, fmt.Errorf(`unexpected %q after array`, p.s[p.i:])
+	}
+	return elems, nil
+}
+
+// subArray parses the braces of an array literal and the elements between them.
+func (p *literalParser) subArray() ([]interface{}, error) {
+	p.skipSpace()
+	if p.i >= len(p.s) || p.s[p.i] != '{' {
+		return nil, fmt.Errorf(`expected "{" in %q`, p.s)
+	}
+	p.i++
+	elems := []interface{}{}
+	p.skipSpace()
+	if p.i < len(p.s) && p.s[p.i] == '}' {
+		p.i++
+		return elems, nil
+	}
+	for {
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return nil, errUnterminated
+		}
+		var elem interface{}
+		switch p.s[p.i] {
+		case '{':
+			sub, err := p.subArray()
+			if err != nil {
+				return nil, err
+			}
+			elem = sub
+		case '"':
+			text, err := p.quoted(false)
+			if err != nil {
+				return nil, err
+			}
+			elem = text
+		default:
+			var b strings.Builder
+			for p.i < len(p.s) && p.s[p.i] != ',' && p.s[p.i] != '}' {
+				if p.s[p.i] == '\\' && p.i+1 < len(p.s) {
+					p.i++
+				}
+				b.WriteByte(p.s[p.i])
+				p.i++
+			}
+			if text := strings.TrimSpace(b.String()); !strings.EqualFold(text, `NULL`) {
+				elem = text
+			}
+		}
+		elems = append(elems, elem)
+		p.skipSpace()
+		if p.i >= len(p.s) {
+			return nil, errUnterminated
+		}
+		switch p.s[p.i] {
+		case ',':
+			p.i++
+		case '}':
+			p.i++
+			return elems, nil
+		default:
+			return nil, fmt.Errorf(`unexpected %q in %q`, p.s[p.i], p.s)
+		}
+	}
+}
+
+// composite parses a composite literal. An empty field is NULL; "" is the empty string.
+func (p *literalParser) composite() ([]interface{}, error) {
+	if !strings.HasPrefix(p.s, `(`) {
+		return nil, fmt.Errorf(`expected "(" in %q`, p.s)
+	}
+	p.i++
+	var fields []interface{}
+	for {
+		var (
+			b     strings.Builder
+			empty = true
+		)
+		for p.i < len(p.s) && p.s[p.i] != ',' && p.s[p.i] != ')' {
+			empty = false
+			switch p.s[p.i] {
+			case '"':
+				text, err := p.quoted(true)
+				if err != nil {
+					return nil, err
+				}
+				b.WriteString(text)
+				continue
+			case '\\':
+				if p.i+1 < len(p.s) {
+					p.i++
+				}
+			}
+			b.WriteByte(p.s[p.i])
+			p.i++
+		}
+		if empty {
+			fields = append(fields, nil)
+		} else {
+			fields = append(fields, b.String())
+		}
+		if p.i >= len(p.s) {
+			return nil, errUnterminated
+		}
+		p.i++
+		if p.s[p.i-1] == ')' {
+			if p.i < len(p.s) {
+				return nil, fmt.Errorf(`unexpected %q after composite`, p.s[p.i:])
+			}
+			return fields, nil
+		}
+	}
+}
+
+// quoted parses a double-quoted string, with backslash escapes and, if doubled, "" for a double quote.
+func (p *literalParser) quoted(doubled bool) (string, error) {
+	var b strings.Builder
+	p.i++ // "
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch c {
+		case '\\':
+			if p.i < len(p.s) {
+				b.WriteByte(p.s[p.i])
+				p.i++
+			}
+			continue
+		case '"':
+			if doubled && p.i < len(p.s) && p.s[p.i] == '"' {
+				p.i++
+				break
+			}
+			return b.String(), nil
+		}
+		b.WriteByte(c)
+	}
+	return ``, errUnterminated
+}
+
+// skipSpace skips whitespace.
+func (p *literalParser) skipSpace() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\n\r\v\f", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
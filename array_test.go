@@ -0,0 +1,159 @@
+package sqlinsert
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type dimensions struct {
+	Width  float64
+	Height float64
+	Label  *string
+}
+
+type candyArrays struct {
+	ID      string      `col:"id"`
+	Tags    []string    `col:"tags,array=text[]"`
+	Counts  [][]int     `col:"counts,array"`
+	Size    *dimensions `col:"size,composite=dimensions"`
+	Flavors []*string   `col:"flavors,array"`
+}
+
+var (
+	flavorNull     = `NULL`
+	flavorQuote    = `say "hi"`
+	candyArraysRec = candyArrays{
+		ID:      `a`,
+		Tags:    []string{`chewy`, `sour, very`, ``, `back\slash`},
+		Counts:  [][]int{{1, 2}, {3, 4}},
+		Size:    &dimensions{Width: 1.5, Height: 2, Label: &flavorQuote},
+		Flavors: []*string{nil, &flavorNull, &flavorQuote},
+	}
+)
+
+/* Postgres arrays and composites */
+
+func TestArgsArray(t *testing.T) {
+	ins := Insert{Table: tbl, Data: []candyArrays{candyArraysRec, {ID: `b`}}}
+	expected := []interface{}{
+		`a`,
+		`{chewy,"sour, very","","back\\slash"}`,
+		`{{1,2},{3,4}}`,
+		`(1.5,2,"say \"hi\"")`,
+		`{NULL,"NULL","say \"hi\""}`,
+		`b`, nil, nil, nil, nil,
+	}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestArgsArrayElements(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	arr, err := encodeArray([]interface{}{true, 1.25, ts, []byte{1, 255}, sql.NullString{}, dimensions{Width: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{t,1.25,"2024-01-02 03:04:05+00:00","\\x01ff",NULL,"(1,0,)"}`
+	if expected != arr {
+		t.Fatalf(`expected "%s", got "%s"`, expected, arr)
+	}
+}
+
+func TestParamsArrayCast(t *testing.T) {
+	defer func(tokenType TokenType) { UseTokenType = tokenType }(UseTokenType)
+	UseTokenType = OrdinalNumberTokenType
+	ins := NewInsert(tbl, candyArraysRec, WithDialect(PostgresDialect))
+	expected := `($1,$2::text[],$3,$4::dimensions,$5)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+}
+
+func TestParseArrayRoundTrip(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyArraysRec}
+	args := ins.Args()
+	var rec candyArrays
+	if err := ParseArray(args[1].(string), &rec.Tags); err != nil {
+		t.Fatal(err)
+	}
+	if err := ScanArray(&rec.Counts).Scan([]byte(args[2].(string))); err != nil {
+		t.Fatal(err)
+	}
+	if err := ScanComposite(&rec.Size).Scan(args[3]); err != nil {
+		t.Fatal(err)
+	}
+	if err := ParseArray(args[4].(string), &rec.Flavors); err != nil {
+		t.Fatal(err)
+	}
+	rec.ID = args[0].(string)
+	if !reflect.DeepEqual(candyArraysRec, rec) {
+		t.Fatalf(`expected "%+v", got "%+v"`, candyArraysRec, rec)
+	}
+}
+
+func TestParseArrayPostgresOutput(t *testing.T) {
+	var stamps []time.Time
+	if err := ParseArray(`[0:1]={"2024-01-02 03:04:05.5+00",NULL}`, &stamps); err != nil {
+		t.Fatal(err)
+	}
+	expected := []time.Time{time.Date(2024, 1, 2, 3, 4, 5, 5e8, time.FixedZone(``, 0)), {}}
+	if len(stamps) != 2 || !stamps[0].Equal(expected[0]) || !stamps[1].IsZero() {
+		t.Fatalf(`expected "%v", got "%v"`, expected, stamps)
+	}
+	var names []sql.NullString
+	if err := ParseArray(`{ a , NULL,"b"}`, &names); err != nil {
+		t.Fatal(err)
+	}
+	expectedNames := []sql.NullString{{String: `a`, Valid: true}, {}, {String: `b`, Valid: true}}
+	if !reflect.DeepEqual(expectedNames, names) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedNames, names)
+	}
+}
+
+func TestParseCompositePostgresOutput(t *testing.T) {
+	var size dimensions
+	if err := ParseComposite(`(1.5,2,"a ""b""")`, &size); err != nil {
+		t.Fatal(err)
+	}
+	if size.Width != 1.5 || size.Height != 2 || size.Label == nil || *size.Label != `a "b"` {
+		t.Fatalf(`unexpected %+v`, size)
+	}
+}
+
+func TestScanArrayNull(t *testing.T) {
+	tags := []string{`x`}
+	if err := ScanArray(&tags).Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if tags != nil {
+		t.Fatalf(`expected nil, got "%v"`, tags)
+	}
+}
+
+func TestParseArrayError(t *testing.T) {
+	var counts []int
+	for _, s := range []string{`{1,2`, `1,2}`, `{1,x}`, `{1,2}x`} {
+		if err := ParseArray(s, &counts); err == nil {
+			t.Fatalf(`expected error for "%s"`, s)
+		}
+	}
+}
+
+func TestCreateTableSQLArray(t *testing.T) {
+	expected := `CREATE TABLE candy (id TEXT NOT NULL,tags TEXT[],counts BIGINT[],size dimensions,flavors TEXT[])`
+	createSQL, err := CreateTableSQL(PostgresDialect, tbl, candyArrays{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected != createSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, createSQL)
+	}
+	if _, err = CreateTableSQL(MySQLDialect, tbl, candyArrays{}); err == nil {
+		t.Fatal(`expected error for composite in MySQL`)
+	}
+}
@@ -0,0 +1,213 @@
+package sqlinsert
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrWriterClosed is the error of writing to an AsyncWriter that is closed.
+var ErrWriterClosed = errors.New(`sqlinsert: AsyncWriter is closed`)
+
+// AsyncWriterConfig configures an AsyncWriter. BatchSize is the number of buffered rows that triggers a flush
+// (default 500), and FlushInterval the longest time between flushes (default 1s). QueueSize is the number of rows
+// that Write queues for the writer before it blocks (default 10 times BatchSize). Options are the Options of the
+// Insert of each flush, e.g., DeadLetters. OnError, if not nil, is called with the error and the rows of each
+// flush that fails.
+type AsyncWriterConfig struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	QueueSize     int
+	Options       []Option
+	OnError       func(err error, rows []interface{})
+}
+
+// AsyncWriter buffers rows written one at a time, e.g., telemetry events, and inserts them in the background with
+// multi-row inserts by Insert.ExecContext, when BatchSize rows are buffered or FlushInterval elapses. It is safe for
+// concurrent use. Close flushes the rows written and stops the writer.
+type AsyncWriter struct {
+	with    InsertWith
+	table   string
+	config  AsyncWriterConfig
+	queue   chan interface{}
+	flushes chan chan error
+	done    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu       sync.RWMutex
+	closed   bool
+	closeErr error
+}
+
+// NewAsyncWriter returns an AsyncWriter that inserts into table on with, e.g., a *sql.DB, and starts it.
+func NewAsyncWriter(with InsertWith, table string, config AsyncWriterConfig) *AsyncWriter {
+	if config.BatchSize < 1 {
+		config.BatchSize = 500
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.QueueSize < 1 {
+		config.QueueSize = 10 * config.BatchSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &AsyncWriter{
+		with:    with,
+		table:   table,
+		config:  config,
+		queue:   make(chan interface{}, config.QueueSize),
+		flushes: make(chan chan error),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		stop:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues the row, a struct or struct pointer, to be inserted. If the queue is full, it blocks until there is
+// room or ctx is done, and then returns ctx.Err(). It returns ErrWriterClosed after Close. The row is inserted
+// later: errors of inserting it go to OnError, and to the next Flush or Close.
+func (w *AsyncWriter) Write(ctx context.Context, row interface{}) error {
+	if _, err := structType(reflect.ValueOf(row), -1); err != nil {
+		return err
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	select {
+	case w.queue <- row:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.stop:
+		return ErrWriterClosed
+	}
+}
+
+// Flush inserts the rows written before it, and returns the error of the first flush that failed since the last
+// Flush, in the background or by this one, if any.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return ErrWriterClosed
+	}
+	select {
+	case w.flushes <- reply:
+	case <-ctx.Done():
+		w.mu.RUnlock()
+		return ctx.Err()
+	case <-w.stop:
+		w.mu.RUnlock()
+		return ErrWriterClosed
+	}
+	w.mu.RUnlock()
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close is CloseContext with context.Background(), which waits as long as the inserts take.
+func (w *AsyncWriter) Close() error {
+	return w.CloseContext(context.Background())
+}
+
+// CloseContext stops accepting rows, waits until the rows written are inserted, and returns the error of the first
+// flush that failed since the last Flush, if any. If ctx is done first, it cancels the insert in progress, so that
+// it and the rows not yet inserted fail (to OnError), and returns ctx.Err(). Close again returns the error of the
+// first flush.
+func (w *AsyncWriter) CloseContext(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop) // Unblock Write and Flush, which hold w.mu while they wait
+	})
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	select {
+	case <-w.done:
+		return w.closeErr
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+// run buffers the queued rows and flushes them until the queue is closed and drained. It keeps the error of the
+// first flush that fails for the next Flush, or for Close.
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	defer w.cancel()
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+	var (
+		buffer []interface{}
+		err    error
+	)
+	flush := func() {
+		if len(buffer) == 0 {
+			return
+		}
+		rows := buffer
+		buffer = nil
+		if flushErr := w.flush(rows); err == nil {
+			err = flushErr
+		}
+	}
+	add := func(row interface{}) {
+		buffer = append(buffer, row)
+		if len(buffer) >= w.config.BatchSize {
+			flush()
+		}
+	}
+	for {
+		select {
+		case row, ok := <-w.queue:
+			if !ok {
+				flush()
+				w.closeErr = err
+				return
+			}
+			add(row)
+		case <-ticker.C:
+			flush()
+		case reply := <-w.flushes:
+			for pending := len(w.queue); pending > 0; pending-- {
+				row, ok := <-w.queue
+				if !ok {
+					break
+				}
+				add(row)
+			}
+			flush()
+			reply <- err
+			err = nil
+		}
+	}
+}
+
+// flush inserts the rows, and calls OnError if that fails.
+func (w *AsyncWriter) flush(rows []interface{}) error {
+	_, err := NewInsert(w.table, rows, w.config.Options...).ExecContext(w.ctx, w.with)
+	if err != nil && w.config.OnError != nil {
+		w.config.OnError(err, rows)
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package sqlinsert
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"testing"
+	"time"
+)
+
+/* AsyncWriter */
+
+type telemetryInsert struct {
+	Id    string `col:"id"`
+	Value int    `col:"value"`
+}
+
+func TestAsyncWriterBatchesAndClose(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO telemetry (id,value) VALUES (?,?),(?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, 1, `b`, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+	s = regexp.QuoteMeta(`INSERT INTO telemetry (id,value) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`c`, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	w := NewAsyncWriter(db, `telemetry`, AsyncWriterConfig{BatchSize: 2, FlushInterval: time.Hour})
+	ctx := context.Background()
+	for _, row := range []interface{}{telemetryInsert{`a`, 1}, &telemetryInsert{`b`, 2}, telemetryInsert{`c`, 3}} {
+		if err = w.Write(ctx, row); err != nil {
+			t.Fatalf(`failed at Write: %s`, err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf(`failed at Close: %s`, err)
+	}
+	if err = w.Write(ctx, telemetryInsert{`d`, 4}); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf(`expected ErrWriterClosed, got "%v"`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestAsyncWriterFlushOnError(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO telemetry (id,value) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, 1).WillReturnError(errConstraint)
+	var failed []interface{}
+	w := NewAsyncWriter(db, `telemetry`, AsyncWriterConfig{
+		FlushInterval: time.Hour,
+		OnError: func(err error, rows []interface{}) {
+			failed = append(failed, rows...)
+		},
+	})
+	ctx := context.Background()
+	if err = w.Write(ctx, telemetryInsert{`a`, 1}); err != nil {
+		t.Fatalf(`failed at Write: %s`, err)
+	}
+	if err = w.Flush(ctx); !errors.Is(err, errConstraint) {
+		t.Fatalf(`expected constraint error, got "%v"`, err)
+	}
+	if len(failed) != 1 || failed[0] != (telemetryInsert{`a`, 1}) {
+		t.Fatalf(`expected the failed row, got %v`, failed)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf(`failed at Close: %s`, err)
+	}
+	if err = w.Write(ctx, nil); err == nil {
+		t.Fatal(`expected error for a nil row`)
+	}
+}
+
+func TestAsyncWriterBackpressure(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO telemetry (id,value) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, 1).WillDelayFor(200 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`b`, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	w := NewAsyncWriter(db, `telemetry`, AsyncWriterConfig{BatchSize: 1, QueueSize: 1, FlushInterval: time.Hour})
+	ctx := context.Background()
+	for _, row := range []telemetryInsert{{`a`, 1}, {`b`, 2}} {
+		if err = w.Write(ctx, row); err != nil {
+			t.Fatalf(`failed at Write: %s`, err)
+		}
+	}
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err = w.Write(timeout, telemetryInsert{`c`, 3}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf(`expected the full queue to block until the deadline, got "%v"`, err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf(`failed at Close: %s`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestAsyncWriterCloseBackgroundError(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO telemetry (id,value) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, 1).WillReturnError(errConstraint)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`b`, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	w := NewAsyncWriter(db, `telemetry`, AsyncWriterConfig{BatchSize: 1, FlushInterval: time.Hour})
+	ctx := context.Background()
+	for _, row := range []telemetryInsert{{`a`, 1}, {`b`, 2}} {
+		if err = w.Write(ctx, row); err != nil {
+			t.Fatalf(`failed at Write: %s`, err)
+		}
+	}
+	if err = w.Close(); !errors.Is(err, errConstraint) {
+		t.Fatalf(`expected constraint error, got "%v"`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestAsyncWriterCloseContext(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO telemetry (id,value) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, 1).WillDelayFor(time.Hour).WillReturnResult(sqlmock.NewResult(0, 1))
+	var failed []interface{}
+	w := NewAsyncWriter(db, `telemetry`, AsyncWriterConfig{
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+		OnError: func(err error, rows []interface{}) {
+			failed = append(failed, rows...)
+		},
+	})
+	if err = w.Write(context.Background(), telemetryInsert{`a`, 1}); err != nil {
+		t.Fatalf(`failed at Write: %s`, err)
+	}
+	timeout, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err = w.CloseContext(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf(`expected the hanging insert to be canceled at the deadline, got "%v"`, err)
+	}
+	if len(failed) != 1 {
+		t.Fatalf(`expected the canceled row, got %v`, failed)
+	}
+}
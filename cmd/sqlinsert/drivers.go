@@ -0,0 +1,8 @@
+package main
+
+// Import the database/sql drivers to build into the command here, for example:
+//
+//	import (
+//		_ "github.com/go-sql-driver/mysql"
+//		_ "github.com/jackc/pgx/v5/stdlib"
+//	)
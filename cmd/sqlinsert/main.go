@@ -0,0 +1,249 @@
+// Command sqlinsert loads data into a database table with the sqlinsert package.
+//
+// Usage:
+//
+//	sqlinsert load-csv -driver NAME -dsn DSN -table TABLE [flags] [FILE]
+//
+// The load-csv subcommand reads a CSV file (or standard input) with a header row and inserts its records with
+// multi-row INSERT statements via database/sql, reading one batch of -batch records at a time, so that a large file
+// is not read into memory. The header names the columns, unless renamed with -map.
+// Run `sqlinsert load-csv -h` for the flags.
+//
+// Database drivers register themselves with database/sql when imported. Import the drivers you need in
+// drivers.go and build the command.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/zachvictor/sqlinsert"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const usage = `usage: sqlinsert load-csv -driver NAME -dsn DSN -table TABLE [flags] [FILE]`
+
+const dialectUsage = `SQL dialect: mysql, postgres, sqlite, sqlserver, oracle, singlestore, cockroachdb`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, `sqlinsert:`, err)
+		os.Exit(1)
+	}
+}
+
+// run runs the subcommand named by args[0] with the rest of args.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case `load-csv`:
+		return loadCSV(args[1:], stdin, stdout, stderr)
+	}
+	return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+}
+
+// loadCSV runs the load-csv subcommand.
+func loadCSV(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet(`load-csv`, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		driverName  = flags.String(`driver`, ``, `database/sql driver `+"`name`")
+		dsn         = flags.String(`dsn`, ``, `data source name`)
+		table       = flags.String(`table`, ``, `table name`)
+		dialectName = flags.String(`dialect`, `mysql`, dialectUsage)
+		batchSize   = flags.Int(`batch`, 500, `rows per INSERT statement`)
+		delimiter   = flags.String(`delimiter`, `,`, `field delimiter`)
+		nullMarker  = flags.String(`null`, ``, "fields equal to `marker` are NULL (default none)")
+		mapping     = flags.String(`map`, ``, "rename columns: `header=column,...`")
+		dryRun      = flags.Bool(`dry-run`, false, `print the SQL instead of executing it`)
+		deadLetter  = flags.String(`dead-letter`, ``, "write the rows that fail to insert to `file` as NDJSON and go on")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	nullSet := false
+	flags.Visit(func(f *flag.Flag) {
+		nullSet = nullSet || f.Name == `null`
+	})
+	if *table == `` || (!*dryRun && (*driverName == `` || *dsn == ``)) {
+		return errors.New(usage)
+	}
+	dialect, err := sqlinsert.ParseDialect(*dialectName)
+	if err != nil {
+		return err
+	}
+	comma, size := utf8.DecodeRuneInString(*delimiter)
+	if size == 0 || size != len(*delimiter) {
+		return fmt.Errorf(`delimiter must be one character, got %q`, *delimiter)
+	}
+	renames, err := parseMapping(*mapping)
+	if err != nil {
+		return err
+	}
+
+	if *batchSize < 1 {
+		return fmt.Errorf(`batch must be at least 1, got %d`, *batchSize)
+	}
+
+	in := stdin
+	if flags.NArg() > 0 {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
+		in = f
+	}
+	reader := csv.NewReader(in)
+	reader.Comma = comma
+	reader.ReuseRecord = true
+	columns, err := readHeader(reader, renames)
+	if err != nil {
+		return err
+	}
+
+	opts := []sqlinsert.Option{sqlinsert.WithDialect(dialect)}
+	if *dryRun {
+		for {
+			rows, err := readRows(reader, columns, *batchSize, *nullMarker, nullSet)
+			if err != nil || len(rows.Values) == 0 {
+				return err
+			}
+			for _, group := range sqlinsert.NewInsert(*table, rows, opts...).DefaultGroups(dialect) {
+				if _, err = fmt.Fprintf(stdout, "%s;\n", group.SQL()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	var letters *offsetDeadLetter
+	if *deadLetter != `` {
+		f, err := os.Create(*deadLetter)
+		if err != nil {
+			return err
+		}
+		defer func(f *os.File) {
+			_ = f.Close()
+		}(f)
+		letters = &offsetDeadLetter{sink: sqlinsert.NewJSONDeadLetter(f)}
+		opts = append(opts, sqlinsert.DeadLetters(letters, sqlinsert.DeadLetterPolicy{Isolate: true}))
+	}
+	db, err := sql.Open(*driverName, *dsn)
+	if err != nil {
+		return err
+	}
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+	ctx := context.Background()
+	read, loaded, failed := 0, 0, 0
+	for {
+		rows, err := readRows(reader, columns, *batchSize, *nullMarker, nullSet)
+		if err != nil {
+			return fmt.Errorf(`after %d rows: %w`, loaded, err)
+		}
+		if len(rows.Values) == 0 {
+			break
+		}
+		if letters != nil {
+			letters.offset = read
+		}
+		result, err := sqlinsert.NewInsert(*table, rows, opts...).ExecContext(ctx, db)
+		if err != nil {
+			return fmt.Errorf(`after %d rows: %w`, loaded, err)
+		}
+		read += result.Rows
+		loaded += result.Rows - len(result.Failed)
+		failed += len(result.Failed)
+	}
+	if failed > 0 {
+		_, err = fmt.Fprintf(stderr, "loaded %d rows into %s, %d failed rows written to %s\n", loaded, *table,
+			failed, *deadLetter)
+		return err
+	}
+	_, err = fmt.Fprintf(stderr, "loaded %d rows into %s\n", loaded, *table)
+	return err
+}
+
+// parseMapping parses header=column pairs separated by commas.
+func parseMapping(mapping string) (map[string]string, error) {
+	renames := make(map[string]string)
+	if mapping == `` {
+		return renames, nil
+	}
+	for _, pair := range strings.Split(mapping, `,`) {
+		i := strings.Index(pair, `=`)
+		if i <= 0 || i == len(pair)-1 {
+			return nil, fmt.Errorf(`map: expected header=column, got %q`, pair)
+		}
+		renames[strings.TrimSpace(pair[:i])] = strings.TrimSpace(pair[i+1:])
+	}
+	return renames, nil
+}
+
+// readHeader reads the header row of a CSV file as column names. Headers are column names unless renamed.
+func readHeader(reader *csv.Reader, renames map[string]string) ([]string, error) {
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New(`missing header row`)
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if column, ok := renames[name]; ok {
+			name = column
+		}
+		columns[i] = name
+	}
+	return columns, nil
+}
+
+// readRows reads the next records of a CSV file, up to n, as rows. The rows have no values at the end of the file.
+// If nullSet, fields equal to nullMarker are NULL.
+func readRows(reader *csv.Reader, columns []string, n int, nullMarker string, nullSet bool) (*sqlinsert.Rows,
+	error) {
+	rows := &sqlinsert.Rows{Columns: columns}
+	for len(rows.Values) < n {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(record))
+		for i, field := range record {
+			if nullSet && field == nullMarker {
+				continue // nil is NULL
+			}
+			values[i] = field
+		}
+		rows.Values = append(rows.Values, values)
+	}
+	return rows, nil
+}
+
+// offsetDeadLetter is a DeadLetter that numbers the rows of a batch by their row in the file, not in the batch.
+type offsetDeadLetter struct {
+	sink   sqlinsert.DeadLetter
+	offset int
+}
+
+// OnDeadLetter sends the event, with the row offset by the rows of the batches before, to the sink.
+func (d *offsetDeadLetter) OnDeadLetter(ctx context.Context, event sqlinsert.DeadLetterEvent) error {
+	event.Row += d.offset
+	return d.sink.OnDeadLetter(ctx, event)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const candyCSV = `id;candy_name;weight
+a;Gougat;1.1
+b;Gumdrop;\N
+c;Toffee;3.1
+`
+
+func TestLoadCSV(t *testing.T) {
+	_, mock, err := sqlmock.NewWithDSN(`load_csv`)
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id,candy_name,weight_grams) VALUES ($1,$2,$3),($4,$5,$6)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).
+		WithArgs(`a`, `Gougat`, `1.1`, `b`, `Gumdrop`, nil).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id,candy_name,weight_grams) VALUES ($1,$2,$3)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`c`, `Toffee`, `3.1`).WillReturnResult(sqlmock.NewResult(3, 1))
+	var stdout, stderr bytes.Buffer
+	err = run([]string{`load-csv`, `-driver`, `sqlmock`, `-dsn`, `load_csv`, `-table`, `candy`,
+		`-dialect`, `postgres`, `-batch`, `2`, `-delimiter`, `;`, `-null`, `\N`, `-map`, `weight=weight_grams`},
+		strings.NewReader(candyCSV), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf(`failed at load-csv %s`, err)
+	}
+	if expected := "loaded 3 rows into candy\n"; expected != stderr.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, stderr.String())
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCSVDeadLetter(t *testing.T) {
+	_, mock, err := sqlmock.NewWithDSN(`load_csv_dead_letter`)
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?),(?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, `Gougat`, `a`, `Gumdrop`).WillReturnError(errors.New(`duplicate key`))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, `Gougat`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, `Gumdrop`).WillReturnError(errors.New(`duplicate key`))
+	deadLetter := filepath.Join(t.TempDir(), `failed.ndjson`)
+	var stdout, stderr bytes.Buffer
+	err = run([]string{`load-csv`, `-driver`, `sqlmock`, `-dsn`, `load_csv_dead_letter`, `-table`, `candy`,
+		`-dead-letter`, deadLetter}, strings.NewReader("id,candy_name\na,Gougat\na,Gumdrop\n"), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf(`failed at load-csv %s`, err)
+	}
+	expected := "loaded 1 rows into candy, 1 failed rows written to " + deadLetter + "\n"
+	if expected != stderr.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, stderr.String())
+	}
+	b, err := os.ReadFile(deadLetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"table":"candy","row":1,"values":{"candy_name":"Gumdrop","id":"a"},` +
+		`"sql":"INSERT INTO candy (id,candy_name) VALUES (?,?)","error":"duplicate key"}` + "\n"
+	if expected != string(b) {
+		t.Fatalf(`expected "%s", got "%s"`, expected, b)
+	}
+}
+
+func TestLoadCSVDeadLetterBatches(t *testing.T) {
+	_, mock, err := sqlmock.NewWithDSN(`load_csv_dead_letter_batches`)
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, `Gougat`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, `Gumdrop`).WillReturnError(errors.New(`duplicate key`))
+	deadLetter := filepath.Join(t.TempDir(), `failed.ndjson`)
+	var stdout, stderr bytes.Buffer
+	err = run([]string{`load-csv`, `-driver`, `sqlmock`, `-dsn`, `load_csv_dead_letter_batches`, `-table`, `candy`,
+		`-batch`, `1`, `-dead-letter`, deadLetter}, strings.NewReader("id,candy_name\na,Gougat\na,Gumdrop\n"),
+		&stdout, &stderr)
+	if err != nil {
+		t.Fatalf(`failed at load-csv %s`, err)
+	}
+	b, err := os.ReadFile(deadLetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"table":"candy","row":1,"values":{"candy_name":"Gumdrop","id":"a"},` +
+		`"sql":"INSERT INTO candy (id,candy_name) VALUES (?,?)","error":"duplicate key"}` + "\n"
+	if expected != string(b) {
+		t.Fatalf(`expected "%s", got "%s"`, expected, b)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCSVDryRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{`load-csv`, `-table`, `candy`, `-batch`, `2`, `-dry-run`},
+		strings.NewReader("id,candy_name\na,Gougat\nb,\nc,Toffee\n"), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf(`failed at load-csv %s`, err)
+	}
+	expected := "INSERT INTO candy (id,candy_name) VALUES (?,?),(?,?);\n" +
+		"INSERT INTO candy (id,candy_name) VALUES (?,?);\n"
+	if expected != stdout.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, stdout.String())
+	}
+}
+
+func TestLoadCSVDryRunOracle(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{`load-csv`, `-table`, `candy`, `-dialect`, `oracle`, `-dry-run`},
+		strings.NewReader("id,candy_name\na,Gougat\nb,Gumdrop\n"), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf(`failed at load-csv %s`, err)
+	}
+	expected := "INSERT ALL INTO candy (id,candy_name) VALUES (:id_1,:candy_name_1) " +
+		"INTO candy (id,candy_name) VALUES (:id_2,:candy_name_2) SELECT 1 FROM DUAL;\n"
+	if expected != stdout.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, stdout.String())
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{`load-json`},
+		{`load-csv`, `-driver`, `sqlmock`, `-dsn`, `load_csv`},
+		{`load-csv`, `-table`, `candy`, `-dry-run`, `-dialect`, `sybase`},
+		{`load-csv`, `-table`, `candy`, `-dry-run`, `-delimiter`, `;;`},
+		{`load-csv`, `-table`, `candy`, `-dry-run`, `-map`, `weight`},
+		{`load-csv`, `-table`, `candy`, `-dry-run`, `-batch`, `0`},
+	} {
+		var stdout, stderr bytes.Buffer
+		if err := run(args, strings.NewReader("id\na\n"), &stdout, &stderr); err == nil {
+			t.Fatalf(`expected error for %v`, args)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// IgnoreConflicts skips the rows that conflict with existing rows, e.g., for idempotent ingestion, in the form of
+// WithDialect:
+//
+//	MySQL, SingleStore   INSERT IGNORE INTO ...
+//	SQLite               INSERT OR IGNORE INTO ..., or ... ON CONFLICT (target) DO NOTHING with a target
+//	Postgres, Cockroach  ... ON CONFLICT DO NOTHING, or ... ON CONFLICT (target) DO NOTHING with a target
+//	SQL Server, Oracle   MERGE INTO ... WHEN NOT MATCHED THEN INSERT ..., matching rows on the target columns
+//
+// The target is the columns of the unique constraint or index to check. It is required in SQL Server and Oracle and
+// not supported in MySQL and SingleStore. An unknown target column, or a dialect with no such form, is an error.
+// InsertResult reports the rows inserted and skipped. The MERGE of SQL Server and Oracle matches rows against the
+// existing rows only, not against one another, so rows of the same target values within one insert are not
+// skipped; they are all inserted or, under a unique constraint, fail the statement.
+func IgnoreConflicts(target ...string) Option {
+	return func(ins *Insert) {
+		options := ins.configure()
+		options.ignoreConflicts = true
+		options.conflictTarget = target
+	}
+}
+
+// Replace switches the statement to REPLACE INTO, which deletes the existing rows that conflict with a new row
+// before inserting it, in MySQL, SingleStore, and SQLite. Any other dialect is an error.
+func Replace() Option {
+	return func(ins *Insert) {
+		ins.configure().statementVerb = `REPLACE INTO`
+	}
+}
+
+// Upsert switches the statement to UPSERT INTO, which overwrites the existing rows that conflict with a new row on
+// the primary key, in CockroachDB. Any other dialect is an error.
+func Upsert() Option {
+	return func(ins *Insert) {
+		ins.configure().statementVerb = `UPSERT INTO`
+	}
+}
+
+// statementVerbs are the dialects that support each statement verb.
+var statementVerbs = map[string][]Dialect{
+	`REPLACE INTO`: {MySQLDialect, SingleStoreDialect, SQLiteDialect},
+	`UPSERT INTO`:  {CockroachDialect},
+}
+
+// checkStatement returns the error of a statement form that the dialect does not support.
+func (ins *Insert) checkStatement(dialect Dialect) error {
+	if ins.options().statementVerb != `` {
+		if ins.options().ignoreConflicts {
+			return fmt.Errorf(`sqlinsert: IgnoreConflicts cannot be combined with %s`, ins.options().statementVerb)
+		}
+		for _, d := range statementVerbs[ins.options().statementVerb] {
+			if d == dialect {
+				return nil
+			}
+		}
+		return fmt.Errorf(`sqlinsert: %s is not supported in dialect %s`, ins.options().statementVerb, dialect)
+	}
+	if !ins.options().ignoreConflicts {
+		return nil
+	}
+	columnNames := ins.columnNames()
+	for _, column := range ins.options().conflictTarget {
+		if indexOf(columnNames, column) < 0 {
+			return fmt.Errorf(`sqlinsert: unknown conflict target column %q`, column)
+		}
+	}
+	switch dialect {
+	case MySQLDialect, SingleStoreDialect:
+		if len(ins.options().conflictTarget) > 0 {
+			return fmt.Errorf(`sqlinsert: IgnoreConflicts takes no conflict target in dialect %s`, dialect)
+		}
+	case SQLServerDialect, OracleDialect:
+		if len(ins.options().conflictTarget) == 0 {
+			return fmt.Errorf(`sqlinsert: IgnoreConflicts requires a conflict target in dialect %s`, dialect)
+		}
+		for _, values := range ins.rowValues() {
+			for _, value := range values {
+				if isDefault(value) {
+					return fmt.Errorf(`sqlinsert: IgnoreConflicts does not support Default in dialect %s`, dialect)
+				}
+			}
+		}
+	case SQLiteDialect, PostgresDialect, CockroachDialect:
+	default:
+		return fmt.Errorf(`sqlinsert: IgnoreConflicts is not supported in dialect %s`, dialect)
+	}
+	return nil
+}
+
+// verb returns the keywords that begin the statement in the dialect.
+func (ins *Insert) verb(dialect Dialect) string {
+	if ins.options().statementVerb != `` {
+		return ins.options().statementVerb
+	}
+	if ins.options().ignoreConflicts {
+		switch {
+		case dialect == MySQLDialect || dialect == SingleStoreDialect:
+			return `INSERT IGNORE INTO`
+		case dialect == SQLiteDialect && len(ins.options().conflictTarget) == 0:
+			return `INSERT OR IGNORE INTO`
+		}
+	}
+	return `INSERT INTO`
+}
+
+// onConflict returns the ON CONFLICT clause that ends the statement in the dialect, if any.
+func (ins *Insert) onConflict(dialect Dialect) string {
+	if !ins.options().ignoreConflicts {
+		return ``
+	}
+	switch {
+	case dialect.postgresLike() && len(ins.options().conflictTarget) == 0:
+		return ` ON CONFLICT DO NOTHING`
+	case dialect.postgresLike() || dialect == SQLiteDialect && len(ins.options().conflictTarget) > 0:
+		return ` ON CONFLICT ` + tokenize(ins.options().conflictTarget, ColumnNameTokenType, 0) + ` DO NOTHING`
+	}
+	return ``
+}
+
+// mergeStatement returns the MERGE statement that inserts the rows of values that do not match existing rows on
+// the conflict target, in SQL Server or Oracle.
+func (ins *Insert) mergeStatement(dialect Dialect, columnNames []string, rows [][]string) string {
+	var (
+		b       strings.Builder
+		matches = make([]string, len(ins.options().conflictTarget))
+		sources = make([]string, len(columnNames))
+	)
+	for i, column := range ins.options().conflictTarget {
+		matches[i] = `target.` + column + ` = source.` + column
+	}
+	for i, column := range columnNames {
+		sources[i] = `source.` + column
+	}
+	columns := tokenize(columnNames, ColumnNameTokenType, 0)
+	if dialect == OracleDialect {
+		_, _ = fmt.Fprintf(&b, `MERGE INTO %s target USING (`, ins.Table)
+		for i, values := range rows {
+			if i > 0 {
+				b.WriteString(` UNION ALL `)
+			}
+			aliased := make([]string, len(values))
+			for j, value := range values {
+				aliased[j] = value + ` ` + columnNames[j]
+			}
+			_, _ = fmt.Fprintf(&b, `SELECT %s FROM DUAL`, strings.Join(aliased, `,`))
+		}
+		_, _ = fmt.Fprintf(&b, `) source ON (%s)`, strings.Join(matches, ` AND `))
+	} else {
+		_, _ = fmt.Fprintf(&b, `MERGE INTO %s AS target USING (VALUES `, ins.Table)
+		for i, values := range rows {
+			if i > 0 {
+				b.WriteString(`,`)
+			}
+			_, _ = fmt.Fprintf(&b, `(%s)`, strings.Join(values, `,`))
+		}
+		_, _ = fmt.Fprintf(&b, `) AS source %s ON %s`, columns, strings.Join(matches, ` AND `))
+	}
+	_, _ = fmt.Fprintf(&b, ` WHEN NOT MATCHED THEN INSERT %s VALUES (%s)`, columns, strings.Join(sources, `,`))
+	if dialect == SQLServerDialect {
+		b.WriteString(`;`) // MERGE requires it
+	}
+	return b.String()
+}
+
+// InsertResult reports the outcome of an insert: Rows is the number of rows of Insert.Data, of which the database
+// inserted Inserted and skipped Skipped, e.g., as conflicts under IgnoreConflicts. Inserted and Skipped are -1 if
+// the driver does not report the rows affected. Under Replace or Upsert, Inserted is the rows affected as the
+// database counts them, e.g., 2 for a row that replaces one in MySQL, and Skipped is -1, since no row is skipped
+// but the count cannot tell inserted rows from replaced ones. Failed are the rows that failed under IsolateErrors,
+// which are neither inserted nor skipped.
+type InsertResult struct {
+	Rows     int
+	Inserted int64
+	Skipped  int64
+	Failed   []*RowError
+
+	results map[int]sql.Result // the result of the statement that inserted each row, by row number
+}
+
+// addStatement adds the rows affected by the statement of ins to Inserted, which becomes -1 if the driver does not
+// report them, and keeps its result for the rows of ins.
+func (r *InsertResult) addStatement(ins *Insert, result sql.Result) {
+	if n, err := result.RowsAffected(); err == nil && r.Inserted >= 0 {
+		r.Inserted += n
+	} else {
+		r.Inserted = -1
+	}
+	if r.results == nil {
+		r.results = make(map[int]sql.Result)
+	}
+	for i := range ins.rows() {
+		r.results[ins.rowNumber(i)] = result
+	}
+}
+
+// add adds the rows of result to the total r.
+func (r *InsertResult) add(result *InsertResult) {
+	r.Failed = append(r.Failed, result.Failed...)
+	r.Rows += result.Rows
+	if r.Inserted >= 0 && result.Inserted >= 0 {
+		r.Inserted += result.Inserted
+	} else {
+		r.Inserted = -1
+	}
+	if r.Skipped >= 0 && result.Skipped >= 0 {
+		r.Skipped += result.Skipped
+	} else {
+		r.Skipped = -1
+	}
+}
+
+// Exec is ExecContext with context.Background().
+func (ins *Insert) Exec(with InsertWith) (*InsertResult, error) {
+	return ins.ExecContext(context.Background(), with)
+}
+
+// ExecContext is InsertContext that returns the InsertResult in place of the prepared statement.
+func (ins *Insert) ExecContext(ctx context.Context, with InsertWith) (*InsertResult, error) {
+	_, result, err := ins.insert(ctx, with)
+	return result, err
+}
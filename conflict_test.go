@@ -0,0 +1,168 @@
+package sqlinsert
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"testing"
+)
+
+/* IgnoreConflicts */
+
+func TestSQLIgnoreConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		dialect   Dialect
+		tokenType TokenType
+		target    []string
+		expected  string
+	}{
+		{MySQLDialect, QuestionMarkTokenType, nil,
+			`INSERT IGNORE INTO candy (id,candy_name) VALUES (?,?),(?,?),(?,?)`},
+		{SQLiteDialect, QuestionMarkTokenType, nil,
+			`INSERT OR IGNORE INTO candy (id,candy_name) VALUES (?,?),(?,?),(?,?)`},
+		{SQLiteDialect, QuestionMarkTokenType, []string{`id`},
+			`INSERT INTO candy (id,candy_name) VALUES (?,?),(?,?),(?,?) ON CONFLICT (id) DO NOTHING`},
+		{PostgresDialect, OrdinalNumberTokenType, nil,
+			`INSERT INTO candy (id,candy_name) VALUES ($1,$2),($3,$4),($5,$6) ON CONFLICT DO NOTHING`},
+		{PostgresDialect, OrdinalNumberTokenType, []string{`id`, `candy_name`},
+			`INSERT INTO candy (id,candy_name) VALUES ($1,$2),($3,$4),($5,$6) ON CONFLICT (id,candy_name) DO NOTHING`},
+		{SQLServerDialect, AtColumnNameTokenType, []string{`id`},
+			`MERGE INTO candy AS target USING (VALUES (@id_1,@candy_name_1),(@id_2,@candy_name_2),(@id_3,@candy_name_3)) ` +
+				`AS source (id,candy_name) ON target.id = source.id ` +
+				`WHEN NOT MATCHED THEN INSERT (id,candy_name) VALUES (source.id,source.candy_name);`},
+		{OracleDialect, QuestionMarkTokenType, []string{`id`},
+			`MERGE INTO candy target USING (SELECT ? id,? candy_name FROM DUAL UNION ALL ` +
+				`SELECT ? id,? candy_name FROM DUAL UNION ALL SELECT ? id,? candy_name FROM DUAL) source ` +
+				`ON (target.id = source.id) ` +
+				`WHEN NOT MATCHED THEN INSERT (id,candy_name) VALUES (source.id,source.candy_name)`},
+	} {
+		ins := NewInsert(tbl, candyRows, IgnoreConflicts(tc.target...), WithDialect(tc.dialect),
+			WithTokenType(tc.tokenType))
+		insertSQL := ins.SQL()
+		if tc.expected != insertSQL {
+			t.Fatalf(`expected "%s", got "%s"`, tc.expected, insertSQL)
+		}
+	}
+}
+
+func TestDebugSQLIgnoreConflicts(t *testing.T) {
+	ins := NewInsert(tbl, Rows{Columns: candyRows.Columns, Values: candyRows.Values[0:1]}, IgnoreConflicts())
+	expected := `INSERT INTO candy (id,candy_name) VALUES ('a','Gougat') ON CONFLICT DO NOTHING`
+	debugSQL := ins.DebugSQL(PostgresDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+func TestBuildIgnoreConflictsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		dialect Dialect
+		target  []string
+	}{
+		{0, nil},
+		{MySQLDialect, []string{`id`}},
+		{SQLServerDialect, nil},
+		{OracleDialect, nil},
+		{PostgresDialect, []string{`nope`}},
+	} {
+		ins := NewInsert(tbl, candyRows, IgnoreConflicts(tc.target...), WithDialect(tc.dialect))
+		if _, _, err := ins.Build(); err == nil {
+			t.Fatalf(`expected error for dialect %s, target %v`, tc.dialect, tc.target)
+		}
+	}
+}
+
+func TestExecContextIgnoreConflicts(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, candyRows, IgnoreConflicts(), WithDialect(MySQLDialect))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(ins.SQL())).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 2))
+	result, err := ins.Exec(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 3 || result.Inserted != 2 || result.Skipped != 1 {
+		t.Fatalf(`expected 3 rows, 2 inserted, 1 skipped, got %+v`, result)
+	}
+}
+
+/* Statement verbs */
+
+func TestSQLReplace(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	for _, dialect := range []Dialect{MySQLDialect, SingleStoreDialect, SQLiteDialect} {
+		ins := NewInsert(tbl, candyRows, Replace(), WithDialect(dialect))
+		expected := `REPLACE INTO candy (id,candy_name) VALUES (?,?),(?,?),(?,?)`
+		insertSQL := ins.SQL()
+		if expected != insertSQL {
+			t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+		}
+	}
+}
+
+func TestExecContextReplace(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, candyRows, Replace(), WithDialect(MySQLDialect))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(ins.SQL())).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 5))
+	result, err := ins.Exec(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 3 || result.Inserted != 5 || result.Skipped != -1 {
+		t.Fatalf(`expected 3 rows, 5 affected, unknown skipped, got %+v`, result)
+	}
+}
+
+func TestSQLUpsert(t *testing.T) {
+	UseTokenType = CockroachDialect.TokenType()
+	ins := NewInsert(tbl, candyRows, Upsert(), WithDialect(CockroachDialect))
+	expected := `UPSERT INTO candy (id,candy_name) VALUES ($1,$2),($3,$4),($5,$6)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestBuildStatementVerbErrors(t *testing.T) {
+	for _, tc := range []struct {
+		dialect Dialect
+		opts    []Option
+	}{
+		{PostgresDialect, []Option{Replace()}},
+		{SQLServerDialect, []Option{Replace()}},
+		{0, []Option{Replace()}},
+		{MySQLDialect, []Option{Upsert()}},
+		{PostgresDialect, []Option{Upsert()}},
+		{SQLiteDialect, []Option{Replace(), IgnoreConflicts()}},
+	} {
+		ins := NewInsert(tbl, candyRows, append(tc.opts, WithDialect(tc.dialect))...)
+		if _, _, err := ins.Build(); err == nil {
+			t.Fatalf(`expected error for dialect %s`, tc.dialect)
+		}
+	}
+}
+
+func TestSQLUnsupportedStatementVerb(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, candyRows, Upsert(), WithDialect(MySQLDialect))
+	expected := `UPSERT INTO candy (id,candy_name) VALUES (?,?),(?,?),(?,?)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestDebugSQLIgnoreConflictsCockroach(t *testing.T) {
+	ins := NewInsert(tbl, Rows{Columns: []string{`id`}, Values: [][]interface{}{{[]byte{1}}}}, IgnoreConflicts(`id`))
+	expected := `INSERT INTO candy (id) VALUES ('\x01'::bytea) ON CONFLICT (id) DO NOTHING`
+	debugSQL := ins.DebugSQL(CockroachDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
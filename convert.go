@@ -0,0 +1,192 @@
+package sqlinsert
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Converter converts a Go value to a bind arg that database/sql drivers accept, e.g., a custom Money type to an
+// int64 of cents.
+type Converter func(v interface{}) (driver.Value, error)
+
+// converterKey identifies the converter of a Go type in a dialect. Dialect 0 is every dialect.
+type converterKey struct {
+	dialect Dialect
+	t       reflect.Type
+}
+
+var (
+	convertersMu sync.RWMutex
+	converters   = make(map[converterKey]Converter)
+)
+
+// RegisterConverter registers conv to convert the bind args of Go type t for every dialect, in place of the
+// driver.Valuer implementation of t, if any. For example:
+//
+//	sqlinsert.RegisterConverter(reflect.TypeOf(netip.Addr{}), func(v interface{}) (driver.Value, error) {
+//		return v.(netip.Addr).String(), nil
+//	})
+//
+// Bind args of type *T are converted by the Converter of T unless *T has its own; nil is NULL.
+func RegisterConverter(t reflect.Type, conv Converter) {
+	RegisterDialectConverter(0, t, conv)
+}
+
+// RegisterDialectConverter registers conv to convert the bind args of Go type t for inserts whose dialect
+// (see WithDialect) is dialect, in place of the Converter registered for every dialect.
+func RegisterDialectConverter(dialect Dialect, t reflect.Type, conv Converter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[converterKey{dialect, t}] = conv
+}
+
+// converter returns the Converter for Go type t in the dialect, if any.
+func converter(dialect Dialect, t reflect.Type) (Converter, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	if conv, ok := converters[converterKey{dialect, t}]; ok {
+		return conv, true
+	}
+	conv, ok := converters[converterKey{0, t}]
+	return conv, ok
+}
+
+// convert converts v with the Converter registered for its type in the dialect, if any; otherwise v is returned
+// as is.
+func convert(dialect Dialect, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	t := reflect.TypeOf(v)
+	if conv, ok := converter(dialect, t); ok {
+		return conv(v)
+	}
+	if t.Kind() == reflect.Pointer {
+		if conv, ok := converter(dialect, t.Elem()); ok {
+			rv := reflect.ValueOf(v)
+			if rv.IsNil() {
+				return nil, nil
+			}
+			return conv(rv.Elem().Interface())
+		}
+	}
+	return v, nil
+}
+
+// ConvertError reports the failure to convert the value of a column of a row of Insert.Data, by a Converter or,
+// for DebugSQL and Script, to a SQL literal. Row is the index of the row. Field is the name of the struct field,
+// if known.
+type ConvertError struct {
+	Row    int
+	Column string
+	Field  string
+	Err    error
+}
+
+// Error returns the error message.
+func (e *ConvertError) Error() string {
+	if e.Field != `` {
+		return fmt.Sprintf(`sqlinsert: row %d, column %s (field %s): %s`, e.Row, e.Column, e.Field, e.Err)
+	}
+	return fmt.Sprintf(`sqlinsert: row %d, column %s: %s`, e.Row, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// bindValues returns the values of each row of Insert.Data, in column order, as bind args: encoded as JSON for
+// fields with the json tag option, otherwise converted by the Converters registered for their types in
+// the dialect of WithDialect. The values of columns whose value expressions take no bind arg are left as they are.
+func (ins *Insert) bindValues(exprs []string) ([][]interface{}, error) {
+	var (
+		rowValues   = ins.rowValues()
+		columnNames = ins.columnNames()
+		tags        = ins.columnTags()
+		fieldNames  = ins.fieldNames()
+	)
+	bindValues := make([][]interface{}, len(rowValues))
+	for i, values := range rowValues {
+		bindValues[i] = make([]interface{}, len(values))
+		for j, value := range values {
+			if !takesArg(exprOf(exprs, j)) {
+				bindValues[i][j] = value
+				continue
+			}
+			var tag columnTag
+			if tags != nil {
+				tag = tags[j]
+			}
+			converted, err := bindValue(ins.options().dialect, tag, value)
+			if err != nil {
+				return nil, ins.convertError(i, j, columnNames, fieldNames, err)
+			}
+			bindValues[i][j] = converted
+		}
+	}
+	return bindValues, nil
+}
+
+// fieldNames returns the struct field names of the columns of Insert.Data that remain after Only and Exclude, in
+// order, or nil for Rows.
+func (ins *Insert) fieldNames() []string {
+	if _, ok := asRows(ins.Data); ok {
+		return nil
+	}
+	t := recordType(ins.Data)
+	fieldNames := make([]string, t.NumField())
+	for i := range fieldNames {
+		fieldNames[i] = t.Field(i).Name
+	}
+	return selectStrings(fieldNames, ins.columnIndexes())
+}
+
+// convertError returns the *ConvertError of column j of row i of Insert.Data, numbered as in the original
+// Insert.Data.
+func (ins *Insert) convertError(i, j int, columnNames, fieldNames []string, err error) *ConvertError {
+	convertErr := &ConvertError{Row: ins.rowNumber(i), Column: columnNames[j], Err: err}
+	if fieldNames != nil {
+		convertErr.Field = fieldNames[j]
+	}
+	return convertErr
+}
+
+// columnTags returns the parsed struct tags of the columns of Insert.Data that remain after Only and Exclude, in
+// order, or nil for Rows.
+func (ins *Insert) columnTags() []columnTag {
+	if _, ok := asRows(ins.Data); ok {
+		return nil
+	}
+	indexes := ins.columnIndexes()
+	t := recordType(ins.Data)
+	tags := make([]columnTag, t.NumField())
+	for i := range tags {
+		tags[i] = parseTag(t.Field(i))
+	}
+	if indexes == nil {
+		return tags
+	}
+	selected := make([]columnTag, len(indexes))
+	for i, index := range indexes {
+		selected[i] = tags[index]
+	}
+	return selected
+}
+
+// bindValue returns v as the bind arg of a column with the given tag: encoded by UseJSONMarshal for the json
+// option, as a Postgres array or composite literal for the array or composite option, otherwise converted by the
+// Converter registered for its type in the dialect, if any.
+func bindValue(dialect Dialect, tag columnTag, v interface{}) (interface{}, error) {
+	switch {
+	case tag.has(`json`):
+		return encodeJSON(v)
+	case tag.has(`array`):
+		return encodeArray(v)
+	case tag.has(`composite`):
+		return encodeComposite(v)
+	}
+	return convert(dialect, v)
+}
@@ -0,0 +1,113 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+type money struct {
+	cents int64
+}
+
+type sku string
+
+type priceInsert struct {
+	Sku      sku    `col:"sku"`
+	Price    money  `col:"price"`
+	Discount *money `col:"discount"`
+}
+
+func init() {
+	RegisterConverter(reflect.TypeOf(money{}), func(v interface{}) (driver.Value, error) {
+		return v.(money).cents, nil
+	})
+	RegisterDialectConverter(PostgresDialect, reflect.TypeOf(money{}), func(v interface{}) (driver.Value, error) {
+		m := v.(money)
+		return fmt.Sprintf(`%d.%02d`, m.cents/100, m.cents%100), nil
+	})
+	RegisterConverter(reflect.TypeOf(sku(``)), func(v interface{}) (driver.Value, error) {
+		if v.(sku) == `` {
+			return nil, errors.New(`empty sku`)
+		}
+		return string(v.(sku)), nil
+	})
+}
+
+/* Converter */
+
+func TestArgsConverter(t *testing.T) {
+	ins := Insert{Table: tbl, Data: []priceInsert{
+		{`a`, money{150}, nil},
+		{`b`, money{275}, &money{25}},
+	}}
+	expected := []interface{}{`a`, int64(150), nil, `b`, int64(275), int64(25)}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestArgsDialectConverter(t *testing.T) {
+	ins := NewInsert(tbl, priceInsert{`a`, money{150}, &money{5}}, WithDialect(PostgresDialect))
+	expected := []interface{}{`a`, `1.50`, `0.05`}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestBuildConvertError(t *testing.T) {
+	ins := Insert{Table: tbl, Data: []*priceInsert{{`a`, money{1}, nil}, {``, money{2}, nil}}}
+	_, _, err := ins.Build()
+	var convertErr *ConvertError
+	if !errors.As(err, &convertErr) {
+		t.Fatalf(`expected ConvertError, got %v`, err)
+	}
+	if convertErr.Row != 1 || convertErr.Column != `sku` || convertErr.Field != `Sku` {
+		t.Fatalf(`unexpected ConvertError %+v`, convertErr)
+	}
+	expected := `sqlinsert: row 1, column sku (field Sku): empty sku`
+	if expected != err.Error() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, err.Error())
+	}
+}
+
+// TestInsertContextConvertError tests that a failed conversion aborts the insert before any SQL is sent
+func TestInsertContextConvertError(t *testing.T) {
+	ins := Insert{Table: tbl, Data: priceInsert{``, money{1}, nil}}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	if _, err = ins.InsertContext(context.Background(), db); err == nil {
+		t.Fatal(`expected error from Converter`)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`expected no SQL, got %s`, err)
+	}
+}
+
+func TestArgsConvertError(t *testing.T) {
+	ins := Insert{Table: tbl, Data: priceInsert{``, money{1}, nil}}
+	if args := ins.Args(); args != nil {
+		t.Fatalf(`expected no args, got "%v"`, args)
+	}
+	var convertErr *ConvertError
+	if _, _, err := ins.Build(); !errors.As(err, &convertErr) {
+		t.Fatalf(`expected ConvertError, got "%v"`, err)
+	}
+}
+
+func TestDebugSQLConverter(t *testing.T) {
+	ins := Insert{Table: tbl, Data: priceInsert{`a`, money{150}, nil}}
+	expected := `INSERT INTO candy (sku,price,discount) VALUES ('a','1.50',NULL)`
+	debugSQL := ins.DebugSQL(PostgresDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
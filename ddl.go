@@ -0,0 +1,320 @@
+package sqlinsert
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// columnDef models the definition of one column in a CREATE TABLE statement.
+type columnDef struct {
+	name       string
+	columnType string
+	nullable   bool
+	primaryKey bool
+	unique     bool
+	dflt       string
+}
+
+// SQL returns the column definition as it appears in a CREATE TABLE statement.
+func (def columnDef) SQL() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, `%s %s`, def.name, def.columnType)
+	if !def.nullable {
+		b.WriteString(` NOT NULL`)
+	}
+	if def.dflt != `` {
+		_, _ = fmt.Fprintf(&b, ` DEFAULT %s`, def.dflt)
+	}
+	if def.unique {
+		b.WriteString(` UNIQUE`)
+	}
+	return b.String()
+}
+
+// dialectTypes names the column types of a dialect. Types containing %s take a size.
+type dialectTypes struct {
+	varchar   string
+	text      string
+	boolean   string
+	smallint  string
+	integer   string
+	bigint    string
+	ubigint   string
+	real      string
+	double    string
+	timestamp string
+	varbinary string
+	blob      string
+	json      string
+}
+
+var mysqlTypes = dialectTypes{
+	varchar:   `VARCHAR(%s)`,
+	text:      `VARCHAR(255)`,
+	boolean:   `BOOLEAN`,
+	smallint:  `SMALLINT`,
+	integer:   `INT`,
+	bigint:    `BIGINT`,
+	ubigint:   `BIGINT UNSIGNED`,
+	real:      `FLOAT`,
+	double:    `DOUBLE`,
+	timestamp: `DATETIME(6)`,
+	varbinary: `VARBINARY(%s)`,
+	blob:      `BLOB`,
+	json:      `JSON`,
+}
+
+var postgresTypes = dialectTypes{
+	varchar:   `VARCHAR(%s)`,
+	text:      `TEXT`,
+	boolean:   `BOOLEAN`,
+	smallint:  `SMALLINT`,
+	integer:   `INTEGER`,
+	bigint:    `BIGINT`,
+	ubigint:   `NUMERIC(20)`,
+	real:      `REAL`,
+	double:    `DOUBLE PRECISION`,
+	timestamp: `TIMESTAMP WITH TIME ZONE`,
+	varbinary: `BYTEA`,
+	blob:      `BYTEA`,
+	json:      `JSONB`,
+}
+
+var columnTypes = map[Dialect]dialectTypes{
+	MySQLDialect:       mysqlTypes,
+	SingleStoreDialect: mysqlTypes,
+	PostgresDialect:    postgresTypes,
+	CockroachDialect:   postgresTypes,
+	SQLiteDialect: {
+		varchar:   `VARCHAR(%s)`,
+		text:      `TEXT`,
+		boolean:   `BOOLEAN`,
+		smallint:  `INTEGER`,
+		integer:   `INTEGER`,
+		bigint:    `INTEGER`,
+		ubigint:   `INTEGER`,
+		real:      `REAL`,
+		double:    `REAL`,
+		timestamp: `DATETIME`,
+		varbinary: `BLOB`,
+		blob:      `BLOB`,
+		json:      `TEXT`,
+	},
+	SQLServerDialect: {
+		varchar:   `NVARCHAR(%s)`,
+		text:      `NVARCHAR(255)`,
+		boolean:   `BIT`,
+		smallint:  `SMALLINT`,
+		integer:   `INT`,
+		bigint:    `BIGINT`,
+		ubigint:   `DECIMAL(20)`,
+		real:      `REAL`,
+		double:    `FLOAT`,
+		timestamp: `DATETIME2`,
+		varbinary: `VARBINARY(%s)`,
+		blob:      `VARBINARY(MAX)`,
+		json:      `NVARCHAR(MAX)`,
+	},
+	OracleDialect: {
+		varchar:   `VARCHAR2(%s)`,
+		text:      `VARCHAR2(255)`,
+		boolean:   `NUMBER(1)`,
+		smallint:  `NUMBER(5)`,
+		integer:   `NUMBER(10)`,
+		bigint:    `NUMBER(19)`,
+		ubigint:   `NUMBER(20)`,
+		real:      `BINARY_FLOAT`,
+		double:    `BINARY_DOUBLE`,
+		timestamp: `TIMESTAMP`,
+		varbinary: `RAW(%s)`,
+		blob:      `BLOB`,
+		json:      `CLOB`,
+	},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// CreateTableSQL returns a CREATE TABLE statement with a column for each field of sample, which may be anything
+// accepted as Insert.Data. Columns are in the same order as Insert.Columns, so the table always accepts the INSERT.
+// Column types derive from Go field types: pointers and sql.Null* types are nullable, all others are NOT NULL.
+// Options after the column name in the struct tag refine the definition:
+//
+//	size=N       length of string and []byte columns, e.g., `col:"id,size=36"`
+//	type=T       column type T verbatim in place of the derived type, e.g., `col:"weight,type=DECIMAL(9,3)"`
+//	null         nullable
+//	notnull      not nullable
+//	pk           member of the primary key (implies notnull)
+//	unique       unique constraint
+//	default=X    default value expression X verbatim, e.g., `col:"ts,default=CURRENT_TIMESTAMP"`
+//	json         JSON column of any Go type, nullable if the type has nil, e.g., `col:"attributes,json"`
+//	json=T       json, and in Postgres, column type T with bind params cast to T, e.g., `col:"attributes,json=jsonb"`
+//	array        Postgres array of the element type (text elsewhere), nullable if a slice, e.g., `col:"tags,array"`
+//	array=T      array, and in Postgres, column type T with bind params cast to T, e.g., `col:"ids,array=uuid[]"`
+//	composite=T  Postgres composite type T with bind params cast to T, e.g., `col:"size,composite=dimensions"`
+func CreateTableSQL(dialect Dialect, table string, sample interface{}) (string, error) {
+	defs, err := columnDefs(dialect, recordType(sample))
+	if err != nil {
+		return ``, err
+	}
+	var (
+		b           strings.Builder
+		primaryKeys []string
+	)
+	_, _ = fmt.Fprintf(&b, `CREATE TABLE %s (`, table)
+	for i, def := range defs {
+		if i > 0 {
+			b.WriteString(`,`)
+		}
+		b.WriteString(def.SQL())
+		if def.primaryKey {
+			primaryKeys = append(primaryKeys, def.name)
+		}
+	}
+	if len(primaryKeys) > 0 {
+		_, _ = fmt.Fprintf(&b, `,PRIMARY KEY (%s)`, strings.Join(primaryKeys, `,`))
+	}
+	b.WriteString(`)`)
+	return b.String(), nil
+}
+
+// columnDefs derives the column definitions of the fields of recordType, in field order.
+func columnDefs(dialect Dialect, recordType reflect.Type) ([]columnDef, error) {
+	types, ok := columnTypes[dialect]
+	if !ok {
+		return nil, fmt.Errorf(`sqlinsert: unsupported dialect %d`, dialect)
+	}
+	defs := make([]columnDef, recordType.NumField())
+	for i := 0; i < recordType.NumField(); i++ {
+		field := recordType.Field(i)
+		tag := parseTag(field)
+		if tag.name == `` {
+			return nil, fmt.Errorf(`sqlinsert: field %s has no column name in struct tag %q`, field.Name, UseStructTag)
+		}
+		valueType, nullable := underlyingType(field.Type)
+		columnType, ok := tag.opts[`type`]
+		if !ok {
+			var err error
+			if columnType, err = types.fieldColumnType(dialect, valueType, tag); err != nil {
+				return nil, fmt.Errorf(`sqlinsert: field %s: %w`, field.Name, err)
+			}
+		}
+		if tag.has(`json`) || tag.has(`array`) || tag.has(`composite`) {
+			switch field.Type.Kind() {
+			case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+				nullable = true // nil is NULL
+			}
+		}
+		defs[i] = columnDef{
+			name:       tag.name,
+			columnType: columnType,
+			nullable:   (nullable || tag.has(`null`)) && !tag.has(`notnull`) && !tag.has(`pk`),
+			primaryKey: tag.has(`pk`),
+			unique:     tag.has(`unique`),
+			dflt:       tag.opts[`default`],
+		}
+	}
+	return defs, nil
+}
+
+// underlyingType unwraps pointers and sql.Null* types to the type of the value they hold, and reports whether any
+// such nullable wrapper was found.
+func underlyingType(t reflect.Type) (reflect.Type, bool) {
+	nullable := false
+	for {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		} else if t.Kind() == reflect.Struct && t.PkgPath() == `database/sql` && t.NumField() == 2 &&
+			t.Field(1).Name == `Valid` {
+			t = t.Field(0).Type // sql.NullString, sql.NullInt64, ..., sql.Null[T]
+		} else {
+			return t, nullable
+		}
+		nullable = true
+	}
+}
+
+// fieldColumnType returns the column type for a field of Go type t with the given tag: for the json, array, and
+// composite options, the type they name in Postgres, if any, or else the JSON type of the dialect or the Postgres
+// array type of the element type; otherwise the column type for values of Go type t.
+func (types dialectTypes) fieldColumnType(dialect Dialect, t reflect.Type, tag columnTag) (string, error) {
+	cast := tag.cast(dialect)
+	switch {
+	case tag.has(`json`):
+		if cast != `` {
+			return strings.ToUpper(cast[2:]), nil
+		}
+		return types.json, nil
+	case tag.has(`array`):
+		if !dialect.postgresLike() {
+			return types.text, nil
+		}
+		if cast != `` {
+			return strings.ToUpper(cast[2:]), nil
+		}
+		for t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			if t.Elem().Kind() == reflect.Uint8 {
+				break
+			}
+			t, _ = underlyingType(t.Elem())
+		}
+		elemType, err := types.columnType(t, ``)
+		if err != nil {
+			return ``, err
+		}
+		return elemType + `[]`, nil
+	case tag.has(`composite`):
+		if cast == `` {
+			return ``, errors.New(`no column type for composite, use "composite=T" in Postgres or the "type" tag option`)
+		}
+		return cast[2:], nil
+	}
+	return types.columnType(t, tag.opts[`size`])
+}
+
+// columnType returns the column type for values of Go type t.
+func (types dialectTypes) columnType(t reflect.Type, size string) (string, error) {
+	if t == timeType {
+		return types.timestamp, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if size != `` {
+			return sized(types.varchar, size), nil
+		}
+		return types.text, nil
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		if size != `` {
+			return sized(types.varbinary, size), nil
+		}
+		return types.blob, nil
+	case reflect.Bool:
+		return types.boolean, nil
+	case reflect.Int8, reflect.Uint8, reflect.Int16:
+		return types.smallint, nil
+	case reflect.Uint16, reflect.Int32:
+		return types.integer, nil
+	case reflect.Uint32, reflect.Int, reflect.Int64:
+		return types.bigint, nil
+	case reflect.Uint, reflect.Uint64:
+		return types.ubigint, nil
+	case reflect.Float32:
+		return types.real, nil
+	case reflect.Float64:
+		return types.double, nil
+	}
+	return ``, fmt.Errorf(`no column type for Go type %s, use the "type" tag option`, t)
+}
+
+// sized fills in the size of a column type that takes one.
+func sized(columnType string, size string) string {
+	if strings.Contains(columnType, `%s`) {
+		return fmt.Sprintf(columnType, size)
+	}
+	return columnType
+}
@@ -0,0 +1,84 @@
+package sqlinsert
+
+import (
+	"strings"
+	"testing"
+)
+
+/* CreateTableSQL */
+
+func TestCreateTableSQLMySQL(t *testing.T) {
+	expected := `CREATE TABLE candy (id VARCHAR(36) NOT NULL,candy_name VARCHAR(255) NOT NULL UNIQUE,` +
+		`form_factor VARCHAR(255),description VARCHAR(255),manufacturer VARCHAR(255),` +
+		`weight_grams DECIMAL(9,3) NOT NULL,stock INT NOT NULL DEFAULT 0,image BLOB NOT NULL,` +
+		`ts DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,PRIMARY KEY (id))`
+	createSQL, err := CreateTableSQL(MySQLDialect, tbl, candyTable{})
+	if err != nil {
+		t.Fatalf(`failed at CreateTableSQL %s`, err)
+	}
+	if expected != createSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, createSQL)
+	}
+}
+
+func TestCreateTableSQLPostgres(t *testing.T) {
+	expected := `CREATE TABLE candy (id VARCHAR(36) NOT NULL,candy_name TEXT NOT NULL UNIQUE,` +
+		`form_factor TEXT,description TEXT,manufacturer TEXT,` +
+		`weight_grams DECIMAL(9,3) NOT NULL,stock INTEGER NOT NULL DEFAULT 0,image BYTEA NOT NULL,` +
+		`ts TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,PRIMARY KEY (id))`
+	createSQL, err := CreateTableSQL(PostgresDialect, tbl, &candyTable{})
+	if err != nil {
+		t.Fatalf(`failed at CreateTableSQL %s`, err)
+	}
+	if expected != createSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, createSQL)
+	}
+}
+
+func TestCreateTableSQLEveryDialect(t *testing.T) {
+	for _, dialect := range []Dialect{
+		MySQLDialect, PostgresDialect, SQLiteDialect, SQLServerDialect, OracleDialect, SingleStoreDialect,
+	} {
+		createSQL, err := CreateTableSQL(dialect, tbl, fiveRecsPointers)
+		if err != nil {
+			t.Fatalf(`failed at CreateTableSQL for %s %s`, dialect, err)
+		}
+		if !strings.HasPrefix(createSQL, `CREATE TABLE candy (id `) {
+			t.Fatalf(`unexpected CREATE TABLE for %s "%s"`, dialect, createSQL)
+		}
+	}
+}
+
+// TestCreateTableSQLColumnOrder tests that the table columns are those of Insert.Columns, in the same order
+func TestCreateTableSQLColumnOrder(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyTable{}}
+	expected := `id,candy_name,form_factor,description,manufacturer,weight_grams,stock,image,ts`
+	columns := strings.Trim(ins.Columns(), `()`)
+	if expected != columns {
+		t.Fatalf(`expected "%s", got "%s"`, expected, columns)
+	}
+}
+
+func TestCreateTableSQLUnsupportedType(t *testing.T) {
+	type unsupported struct {
+		Tags []string `col:"tags"`
+	}
+	if _, err := CreateTableSQL(PostgresDialect, tbl, unsupported{}); err == nil {
+		t.Fatal(`expected error for unsupported Go type`)
+	}
+}
+
+func TestCreateTableSQLMissingColumnName(t *testing.T) {
+	type untagged struct {
+		Id string
+	}
+	if _, err := CreateTableSQL(PostgresDialect, tbl, untagged{}); err == nil {
+		t.Fatal(`expected error for field without column name`)
+	}
+}
+
+func TestCreateTableSQLUnsupportedDialect(t *testing.T) {
+	if _, err := CreateTableSQL(Dialect(0), tbl, recValue); err == nil {
+		t.Fatal(`expected error for unsupported dialect`)
+	}
+}
@@ -0,0 +1,172 @@
+package sqlinsert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DeadLetter models a sink for the rows that fail to insert, e.g., to inspect or replay them later, so that they
+// are kept rather than dropped. OnDeadLetter is called once per failed row; an error fails the insert.
+type DeadLetter interface {
+	OnDeadLetter(ctx context.Context, event DeadLetterEvent) error
+}
+
+// DeadLetterEvent describes a row that failed to insert. Table is the table of the insert, Row is the index of the
+// row in Insert.Data, and Data is the row, as in RowError. Columns and Values are the column names and values of
+// all the columns of the row. SQL is the statement that failed and Err its error.
+type DeadLetterEvent struct {
+	Table   string
+	Row     int
+	Data    interface{}
+	Columns []string
+	Values  []interface{}
+	SQL     string
+	Err     error
+}
+
+// DeadLetterPolicy specifies the rows that an Insert sends to its DeadLetter. If Isolate is true, the failing rows
+// of a failing statement are isolated, as by IsolateErrors, and only they are sent; otherwise every row of a
+// failing statement is, which, in a *sql.Tx, runs inside a savepoint all the same. MaxRows, if not 0, is the number
+// of rows sent (by every Insert with the same DeadLetters option, e.g., the batches of a stream) beyond which the
+// insert fails, having sent the rows of the statement.
+type DeadLetterPolicy struct {
+	Isolate bool
+	MaxRows int
+}
+
+// DeadLetters sends the rows that fail to insert to sink according to policy, and continues: Insert.ExecContext
+// and Insert.InsertContext return no error for them, and InsertResult.Failed reports them. The DeadLetter in a
+// *sql.Tx should use another connection, e.g., the *sql.DB, so that the rows it keeps are not rolled back with the
+// transaction.
+func DeadLetters(sink DeadLetter, policy DeadLetterPolicy) Option {
+	letters := &deadLetters{sink: sink, policy: policy}
+	return func(ins *Insert) {
+		ins.configure().deadLetters = letters
+	}
+}
+
+// deadLetters is the DeadLetters option, shared by the inserts it configures.
+type deadLetters struct {
+	sink   DeadLetter
+	policy DeadLetterPolicy
+	mu     sync.Mutex
+	sent   int
+}
+
+// send sends the failed rows of the insert to the DeadLetter. It returns the error of the DeadLetter, or, if more
+// than MaxRows have been sent, the RowErrors.
+func (d *deadLetters) send(ctx context.Context, ins *Insert, failed []*RowError) error {
+	for _, rowErr := range failed {
+		columns, values := ins.rowColumns(rowErr.Data)
+		event := DeadLetterEvent{
+			Table:   ins.Table,
+			Row:     rowErr.Row,
+			Data:    rowErr.Data,
+			Columns: columns,
+			Values:  values,
+			SQL:     rowErr.SQL,
+			Err:     rowErr.Err,
+		}
+		if err := d.sink.OnDeadLetter(ctx, event); err != nil {
+			return fmt.Errorf(`sqlinsert: dead letter of row %d: %w`, rowErr.Row, err)
+		}
+	}
+	d.mu.Lock()
+	d.sent += len(failed)
+	sent := d.sent
+	d.mu.Unlock()
+	if d.policy.MaxRows > 0 && sent > d.policy.MaxRows {
+		return fmt.Errorf(`sqlinsert: %d rows failed, more than %d: %w`, sent, d.policy.MaxRows, RowErrors(failed))
+	}
+	return nil
+}
+
+// rowColumns returns the column names and values of all the columns of a row of Insert.Data, as RowError.Data.
+func (ins *Insert) rowColumns(data interface{}) ([]string, []interface{}) {
+	if rows, ok := asRows(ins.Data); ok {
+		return rows.Columns, data.([]interface{})
+	}
+	row := Insert{Data: data}
+	return row.allColumnNames(), row.rowValues()[0]
+}
+
+// rowJSON encodes the values of a row as a JSON object of column names to values by UseJSONMarshal, leaving out
+// Default values.
+func rowJSON(columns []string, values []interface{}) ([]byte, error) {
+	object := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if !isDefault(values[i]) {
+			object[column] = values[i]
+		}
+	}
+	return UseJSONMarshal(object)
+}
+
+// JSONDeadLetter is a DeadLetter that writes each failed row as a line of newline-delimited JSON (NDJSON) to an
+// io.Writer, e.g., a file, with the table, the row index, the values of the row by column, the SQL, and the error:
+//
+//	{"table":"candy","row":17,"values":{"candy_name":"Gougat","id":"..."},"sql":"INSERT ...","error":"..."}
+//
+// It is safe for concurrent use.
+type JSONDeadLetter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONDeadLetter returns a JSONDeadLetter that writes to w.
+func NewJSONDeadLetter(w io.Writer) *JSONDeadLetter {
+	return &JSONDeadLetter{w: w}
+}
+
+// OnDeadLetter writes the line of the failed row.
+func (d *JSONDeadLetter) OnDeadLetter(_ context.Context, event DeadLetterEvent) error {
+	values, err := rowJSON(event.Columns, event.Values)
+	if err != nil {
+		return err
+	}
+	line, err := UseJSONMarshal(struct {
+		Table  string          `json:"table"`
+		Row    int             `json:"row"`
+		Values json.RawMessage `json:"values"`
+		SQL    string          `json:"sql"`
+		Error  string          `json:"error"`
+	}{event.Table, event.Row, values, event.SQL, event.Err.Error()})
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err = d.w.Write(append(line, '\n'))
+	return err
+}
+
+// TableDeadLetter is a DeadLetter that inserts each failed row into Table with an Insert in Dialect on With, e.g.,
+// a *sql.DB, as a row with the columns:
+//
+//	table_name  the table of the failed insert
+//	row_index   the index of the row in Insert.Data
+//	row_data    the values of the row by column as a JSON object, as JSONDeadLetter writes them
+//	query       the SQL that failed
+//	error       the error message
+type TableDeadLetter struct {
+	Table   string
+	Dialect Dialect
+	With    InsertWith
+}
+
+// OnDeadLetter inserts the row of the failed row.
+func (d *TableDeadLetter) OnDeadLetter(ctx context.Context, event DeadLetterEvent) error {
+	values, err := rowJSON(event.Columns, event.Values)
+	if err != nil {
+		return err
+	}
+	ins := NewInsert(d.Table, Rows{
+		Columns: []string{`table_name`, `row_index`, `row_data`, `query`, `error`},
+		Values:  [][]interface{}{{event.Table, event.Row, string(values), event.SQL, event.Err.Error()}},
+	}, WithDialect(d.Dialect))
+	_, err = ins.InsertContext(ctx, d.With)
+	return err
+}
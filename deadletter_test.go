@@ -0,0 +1,165 @@
+package sqlinsert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+/* DeadLetters */
+
+// deadLetterRecorder is a DeadLetter that keeps its events in memory.
+type deadLetterRecorder struct {
+	events []DeadLetterEvent
+}
+
+func (r *deadLetterRecorder) OnDeadLetter(_ context.Context, event DeadLetterEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestExecContextDeadLettersBatch(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rows := Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}, {`b`}, {`c`}}}
+	sink := &deadLetterRecorder{}
+	ins := NewInsert(tbl, rows, DeadLetters(sink, DeadLetterPolicy{}))
+	batches := ins.Batches(2)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnResult(sqlmock.NewResult(0, 2))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnError(errConstraint)
+	for i, batch := range batches {
+		result, err := batch.ExecContext(context.Background(), db)
+		if err != nil {
+			t.Fatalf(`failed at ExecContext of batch %d: %s`, i, err)
+		}
+		if i == 1 && (len(result.Failed) != 1 || result.Inserted != 0 || result.Skipped != 0) {
+			t.Fatalf(`expected 1 failed row, got %+v`, result)
+		}
+	}
+	if len(sink.events) != 1 {
+		t.Fatalf(`expected 1 dead letter, got %d`, len(sink.events))
+	}
+	event := sink.events[0]
+	if event.Table != tbl || event.Row != 2 || event.Columns[0] != `id` || event.Values[0] != `c` ||
+		event.SQL != `INSERT INTO candy (id) VALUES (?)` || !errors.Is(event.Err, errConstraint) {
+		t.Fatalf(`unexpected dead letter %+v`, event)
+	}
+}
+
+func TestExecContextDeadLettersTx(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rows := Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}, {`b`}}}
+	sink := &deadLetterRecorder{}
+	ins := NewInsert(tbl, rows, DeadLetters(sink, DeadLetterPolicy{}))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT sqlinsert_isolate`).WillReturnResult(sqlmock.NewResult(0, 0))
+	s := regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnError(errConstraint)
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT sqlinsert_isolate`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf(`failed at Begin: %s`, err)
+	}
+	result, err := ins.ExecContext(context.Background(), tx)
+	if err != nil {
+		t.Fatalf(`failed at ExecContext: %s`, err)
+	}
+	if len(result.Failed) != 2 || len(sink.events) != 2 {
+		t.Fatalf(`expected 2 failed rows, got %+v`, result)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf(`failed at Commit: %s`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestExecContextDeadLettersMaxRows(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	recs := []candyInsert{recValue, recValue}
+	sink := &deadLetterRecorder{}
+	ins := NewInsert(tbl, recs, Only(`id`), DeadLetters(sink, DeadLetterPolicy{Isolate: true, MaxRows: 1}))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	for _, s := range []string{
+		`INSERT INTO candy (id) VALUES (?),(?)`, `INSERT INTO candy (id) VALUES (?)`, `INSERT INTO candy (id) VALUES (?)`,
+	} {
+		mock.ExpectPrepare(regexp.QuoteMeta(s))
+		mock.ExpectExec(regexp.QuoteMeta(s)).WillReturnError(errConstraint)
+	}
+	_, err = ins.ExecContext(context.Background(), db)
+	if err == nil || !strings.Contains(err.Error(), `2 rows failed, more than 1`) || !errors.Is(err, errConstraint) {
+		t.Fatalf(`expected MaxRows error, got "%v"`, err)
+	}
+	if len(sink.events) != 2 || sink.events[1].Data != &recs[1] || len(sink.events[1].Columns) != 7 {
+		t.Fatalf(`expected 2 dead letters of the full rows, got %+v`, sink.events)
+	}
+}
+
+func TestJSONDeadLetter(t *testing.T) {
+	var b bytes.Buffer
+	event := DeadLetterEvent{
+		Table:   tbl,
+		Row:     3,
+		Columns: []string{`id`, `ts`, `n`},
+		Values:  []interface{}{`a`, Default, 2},
+		SQL:     `INSERT INTO candy (id,ts,n) VALUES (?,DEFAULT,?)`,
+		Err:     errConstraint,
+	}
+	if err := NewJSONDeadLetter(&b).OnDeadLetter(context.Background(), event); err != nil {
+		t.Fatalf(`failed at OnDeadLetter: %s`, err)
+	}
+	expected := `{"table":"candy","row":3,"values":{"id":"a","n":2},` +
+		`"sql":"INSERT INTO candy (id,ts,n) VALUES (?,DEFAULT,?)","error":"constraint violated"}` + "\n"
+	if expected != b.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, b.String())
+	}
+}
+
+func TestTableDeadLetter(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO dead_letter (table_name,row_index,row_data,query,error) VALUES (?,?,?,?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).
+		WithArgs(tbl, 3, `{"id":"a"}`, `INSERT INTO candy (id) VALUES (?)`, `constraint violated`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sink := &TableDeadLetter{Table: `dead_letter`, With: db}
+	event := DeadLetterEvent{
+		Table:   tbl,
+		Row:     3,
+		Columns: []string{`id`},
+		Values:  []interface{}{`a`},
+		SQL:     `INSERT INTO candy (id) VALUES (?)`,
+		Err:     errConstraint,
+	}
+	if err = sink.OnDeadLetter(context.Background(), event); err != nil {
+		t.Fatalf(`failed at OnDeadLetter: %s`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
@@ -0,0 +1,128 @@
+package sqlinsert
+
+import (
+	"reflect"
+	"strings"
+)
+
+// DefaultZero inserts the column default in place of every zero value, including nil, as the defaultzero tag option
+// does for one field, e.g., `col:"status,defaultzero"`: the DEFAULT keyword takes the place of its bind param, and
+// Args leaves it out, as for Default.
+func DefaultZero() Option {
+	return func(ins *Insert) {
+		ins.configure().defaultZero = true
+	}
+}
+
+// DefaultGroups returns the insert itself, unless the dialect has no DEFAULT keyword in VALUES (SQLite) and a row
+// has a Default value (or a zero value, see DefaultZero). Then it returns one insert per group of rows that have
+// the same columns with Default values, in order of their first row, each leaving those columns out. A row whose
+// columns are all Default is inserted alone, with DEFAULT VALUES. Insert.InsertContext and Script insert the
+// groups in turn.
+func (ins *Insert) DefaultGroups(dialect Dialect) []Insert {
+	if dialect != SQLiteDialect {
+		return []Insert{*ins}
+	}
+	var (
+		resolved    = ins.resolved()
+		columnNames = resolved.columnNames()
+		keys        []string
+		groups      = make(map[string][]int)
+	)
+	for i, values := range resolved.rowValues() {
+		key := make([]byte, len(values))
+		for j, value := range values {
+			key[j] = '0'
+			if isDefault(value) {
+				key[j] = '1'
+			}
+		}
+		if _, ok := groups[string(key)]; !ok {
+			keys = append(keys, string(key))
+		}
+		groups[string(key)] = append(groups[string(key)], i)
+	}
+	if len(keys) == 1 && !strings.Contains(keys[0], `1`) {
+		return []Insert{*ins}
+	}
+	var inserts []Insert
+	for _, key := range keys {
+		omit := append([]string(nil), ins.options().omit...)
+		for j := range key {
+			if key[j] == '1' {
+				omit = append(omit, columnNames[j])
+			}
+		}
+		rowGroups := [][]int{groups[key]}
+		if !strings.Contains(key, `0`) { // DEFAULT VALUES inserts one row
+			rowGroups = rowGroups[:0]
+			for _, i := range groups[key] {
+				rowGroups = append(rowGroups, []int{i})
+			}
+		}
+		for _, rowIndexes := range rowGroups {
+			group := resolved.subset(rowIndexes)
+			group.configure().omit = omit
+			inserts = append(inserts, group)
+		}
+	}
+	return inserts
+}
+
+// subset returns the insert of the rows of Insert.Data at the indexes, which keeps their row numbers in the
+// original Insert.Data for RowError and ConvertError.
+func (ins *Insert) subset(indexes []int) Insert {
+	rowIndex := make([]int, len(indexes))
+	for i, index := range indexes {
+		rowIndex[i] = ins.rowNumber(index)
+	}
+	return ins.derive(ins.selectRows(indexes), rowIndex)
+}
+
+// rowNumber returns the index of row i of Insert.Data in the original Insert.Data, of which it may be a subset.
+func (ins *Insert) rowNumber(i int) int {
+	if ins.options().rowIndex == nil {
+		return i
+	}
+	return ins.options().rowIndex[i]
+}
+
+// selectRows returns the rows of Insert.Data at the indexes, in the form of Insert.Data (Rows for Rows or Maps),
+// except that struct values are selected as pointers to them, to keep the rows those of Insert.Data.
+func (ins *Insert) selectRows(indexes []int) interface{} {
+	if rows, ok := asRows(ins.Data); ok {
+		selected := &Rows{Columns: rows.Columns, Values: make([][]interface{}, len(indexes))}
+		for i, index := range indexes {
+			selected.Values[i] = rows.Values[index]
+		}
+		return selected
+	}
+	data := reflect.ValueOf(ins.Data)
+	if data.Kind() != reflect.Slice {
+		return ins.Data
+	}
+	if data.Type().Elem().Kind() == reflect.Struct {
+		selected := reflect.MakeSlice(reflect.SliceOf(reflect.PointerTo(data.Type().Elem())), len(indexes), len(indexes))
+		for i, index := range indexes {
+			selected.Index(i).Set(data.Index(index).Addr())
+		}
+		return selected.Interface()
+	}
+	selected := reflect.MakeSlice(data.Type(), len(indexes), len(indexes))
+	for i, index := range indexes {
+		selected.Index(i).Set(data.Index(index))
+	}
+	return selected.Interface()
+}
+
+// defaultZeros returns values with Default in place of each zero value, including nil.
+func defaultZeros(values []interface{}) []interface{} {
+	defaulted := make([]interface{}, len(values))
+	for i, value := range values {
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			value = Default
+		}
+		defaulted[i] = value
+	}
+	return defaulted
+}
@@ -0,0 +1,145 @@
+package sqlinsert
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type candyStatus struct {
+	ID     string     `col:"id"`
+	Status string     `col:"status,defaultzero"`
+	Stock  int        `col:"stock,defaultzero"`
+	Ts     *time.Time `col:"ts,defaultzero"`
+}
+
+var candyStatusRecs = []candyStatus{
+	{ID: `a`, Status: `new`},
+	{ID: `b`, Stock: 3},
+	{ID: `c`},
+	{ID: `d`, Status: `old`, Stock: 1},
+}
+
+/* Defaults */
+
+// - defaultzero
+
+func TestParamsDefaultZeroTag(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	ins := Insert{Table: tbl, Data: candyStatusRecs}
+	expected := `($1,$2,DEFAULT,DEFAULT),($3,DEFAULT,$4,DEFAULT),($5,DEFAULT,DEFAULT,DEFAULT),($6,$7,$8,DEFAULT)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+	expectedArgs := []interface{}{`a`, `new`, `b`, 3, `c`, `d`, `old`, 1}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestParamsDefaultZeroOption(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, Rows{Columns: []string{`id`, `n`}, Values: [][]interface{}{{`a`, nil}, {``, 0}, {`b`, 2}}},
+		DefaultZero())
+	expected := `(?,DEFAULT),(DEFAULT,DEFAULT),(?,?)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+	expectedArgs := []interface{}{`a`, `b`, 2}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+// - SQLite
+
+func TestDefaultGroupsSQLite(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, candyStatusRecs, Exclude(`ts`))
+	groups := ins.DefaultGroups(SQLiteDialect)
+	expected := []string{
+		`INSERT INTO candy (id,status) VALUES (?,?)`,
+		`INSERT INTO candy (id,stock) VALUES (?,?)`,
+		`INSERT INTO candy (id) VALUES (?)`,
+		`INSERT INTO candy (id,status,stock) VALUES (?,?,?)`,
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf(`expected %d groups, got %d`, len(expected), len(groups))
+	}
+	for i := range groups {
+		if insertSQL := groups[i].SQL(); expected[i] != insertSQL {
+			t.Fatalf(`expected "%s", got "%s"`, expected[i], insertSQL)
+		}
+	}
+	expectedArgs := []interface{}{`d`, `old`, 1}
+	args := groups[3].Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestDefaultGroupsSQLiteDefaultValues(t *testing.T) {
+	ins := Insert{Table: tbl, Data: Rows{Columns: []string{`ts`}, Values: [][]interface{}{{Default}, {Default}}}}
+	groups := ins.DefaultGroups(SQLiteDialect)
+	if len(groups) != 2 {
+		t.Fatalf(`expected 2 groups, got %d`, len(groups))
+	}
+	expected := `INSERT INTO candy DEFAULT VALUES`
+	if insertSQL := groups[1].SQL(); expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestDefaultGroupsOtherDialect(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyStatusRecs}
+	if groups := ins.DefaultGroups(PostgresDialect); len(groups) != 1 {
+		t.Fatalf(`expected 1 group, got %d`, len(groups))
+	}
+}
+
+func TestInsertContextSQLiteDefaults(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, candyStatusRecs[:3], WithDialect(SQLiteDialect))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	for _, e := range []struct {
+		query string
+		args  []driver.Value
+	}{
+		{`INSERT INTO candy (id,status) VALUES (?,?)`, []driver.Value{`a`, `new`}},
+		{`INSERT INTO candy (id,stock) VALUES (?,?)`, []driver.Value{`b`, int64(3)}},
+		{`INSERT INTO candy (id) VALUES (?)`, []driver.Value{`c`}},
+	} {
+		mock.ExpectPrepare(regexp.QuoteMeta(e.query)).ExpectExec().WithArgs(e.args...).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	if _, err = ins.InsertContext(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScriptSQLiteDefaults(t *testing.T) {
+	var b bytes.Buffer
+	ins := Insert{Table: tbl, Data: candyStatusRecs[1:3]}
+	if err := (Script{Dialect: SQLiteDialect}).Write(&b, ins); err != nil {
+		t.Fatal(err)
+	}
+	expected := "INSERT INTO candy (id,stock) VALUES ('b',3);\nINSERT INTO candy (id) VALUES ('c');\n"
+	if expected != b.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, b.String())
+	}
+}
@@ -0,0 +1,111 @@
+package sqlinsert
+
+import "fmt"
+
+// Dialect represents a SQL database vendor whose syntax and column types differ from the others.
+type Dialect int
+
+const (
+
+	// MySQLDialect is MySQL and MariaDB.
+	MySQLDialect Dialect = 1
+
+	// PostgresDialect is PostgreSQL.
+	PostgresDialect Dialect = 2
+
+	// SQLiteDialect is SQLite.
+	SQLiteDialect Dialect = 3
+
+	// SQLServerDialect is Microsoft SQL Server (T-SQL).
+	SQLServerDialect Dialect = 4
+
+	// OracleDialect is Oracle Database.
+	OracleDialect Dialect = 5
+
+	// SingleStoreDialect is SingleStore (MemSQL), which follows MySQL syntax.
+	SingleStoreDialect Dialect = 6
+
+	// CockroachDialect is CockroachDB, which follows PostgreSQL syntax.
+	CockroachDialect Dialect = 7
+)
+
+// WithDialect sets the Dialect of the Insert, which selects the forms of its statement, e.g., of IgnoreConflicts,
+// the Converters registered for the dialect, and the token type of its bind params: Dialect.TokenType, e.g., $1 for
+// PostgresDialect, in place of UseTokenType, unless WithTokenType sets another.
+func WithDialect(dialect Dialect) Option {
+	return func(ins *Insert) {
+		ins.configure().dialect = dialect
+	}
+}
+
+// WithTokenType sets the token type of the bind params of the Insert, in place of that of its Dialect or
+// UseTokenType, e.g., AtColumnNameTokenType for a driver that binds SQL Server params by name.
+func WithTokenType(tokenType TokenType) Option {
+	return func(ins *Insert) {
+		options := ins.configure()
+		options.tokenType, options.tokenTyped = tokenType, true
+	}
+}
+
+// tokenType returns the token type of the bind params of the Insert: that of WithTokenType, or of its Dialect, or
+// else UseTokenType.
+func (ins *Insert) tokenType() TokenType {
+	switch {
+	case ins.options().tokenTyped:
+		return ins.options().tokenType
+	case ins.options().dialect != 0:
+		return ins.options().dialect.TokenType()
+	}
+	return UseTokenType
+}
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case MySQLDialect:
+		return `mysql`
+	case PostgresDialect:
+		return `postgres`
+	case SQLiteDialect:
+		return `sqlite`
+	case SQLServerDialect:
+		return `sqlserver`
+	case OracleDialect:
+		return `oracle`
+	case SingleStoreDialect:
+		return `singlestore`
+	case CockroachDialect:
+		return `cockroachdb`
+	default:
+		return `unknown`
+	}
+}
+
+// ParseDialect returns the dialect named by name, as returned by Dialect.String.
+func ParseDialect(name string) (Dialect, error) {
+	for d := MySQLDialect; d <= CockroachDialect; d++ {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf(`sqlinsert: unknown dialect %q`, name)
+}
+
+// postgresLike reports whether the dialect follows PostgreSQL syntax.
+func (d Dialect) postgresLike() bool {
+	return d == PostgresDialect || d == CockroachDialect
+}
+
+// TokenType returns the customary bind param token type of the dialect.
+func (d Dialect) TokenType() TokenType {
+	switch d {
+	case PostgresDialect, CockroachDialect:
+		return OrdinalNumberTokenType
+	case SQLServerDialect:
+		return AtPOrdinalTokenType
+	case OracleDialect:
+		return ColonTokenType
+	default:
+		return QuestionMarkTokenType
+	}
+}
@@ -0,0 +1,41 @@
+package sqlinsert
+
+import "testing"
+
+func TestParseDialect(t *testing.T) {
+	for _, dialect := range []Dialect{
+		MySQLDialect, PostgresDialect, SQLiteDialect, SQLServerDialect, OracleDialect, SingleStoreDialect,
+		CockroachDialect,
+	} {
+		parsed, err := ParseDialect(dialect.String())
+		if err != nil {
+			t.Fatalf(`failed at ParseDialect %s`, err)
+		}
+		if dialect != parsed {
+			t.Fatalf(`expected "%s", got "%s"`, dialect, parsed)
+		}
+	}
+	if _, err := ParseDialect(`sybase`); err == nil {
+		t.Fatal(`expected error for unknown dialect`)
+	}
+}
+
+func TestWithDialectTokenType(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	for _, tc := range []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil, `INSERT INTO candy (id,candy_name) VALUES (?,?)`},
+		{[]Option{WithDialect(PostgresDialect)}, `INSERT INTO candy (id,candy_name) VALUES ($1,$2)`},
+		{[]Option{WithDialect(SQLServerDialect)}, `INSERT INTO candy (id,candy_name) VALUES (@p1,@p2)`},
+		{[]Option{WithDialect(SQLServerDialect), WithTokenType(AtColumnNameTokenType)},
+			`INSERT INTO candy (id,candy_name) VALUES (@id,@candy_name)`},
+	} {
+		ins := NewInsert(tbl, Rows{Columns: candyRows.Columns, Values: candyRows.Values[0:1]}, tc.opts...)
+		insertSQL := ins.SQL()
+		if tc.expected != insertSQL {
+			t.Fatalf(`expected "%s", got "%s"`, tc.expected, insertSQL)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package sqlinsert
+
+import "fmt"
+
+// Expr sets the SQL expression of the values of the named column in place of its bind param, e.g., `NOW()` or
+// `ST_GeomFromText(?)`, as the expr tag option does, e.g., `col:"created_at,expr=NOW()"`. A ? in the expression
+// (outside single quotes and double quotes) is the bind param of the column, numbered or named as UseTokenType
+// requires, and binds the value of the column; an expression with no ? takes no bind arg, so Args leaves the value
+// out. Expr takes precedence over the tag option. A name that is not a column of Insert.Data is an error, as is an
+// expression with more than one ?.
+func Expr(column string, expr string) Option {
+	return func(ins *Insert) {
+		options := ins.configure()
+		exprs := make(map[string]string, len(options.exprs)+1) // copied, since copies of the Insert share the map
+		for c, e := range options.exprs {
+			exprs[c] = e
+		}
+		exprs[column] = expr
+		options.exprs = exprs
+	}
+}
+
+// valueExprs returns the value expressions of the columns of Insert.Data in the dialect, in order, from Expr, the
+// expr tag option, or the Postgres cast of the json, array, or composite tag option, e.g., `?::jsonb`. A column
+// with no expression is the bind param alone, and the result is nil if no column has one.
+func (ins *Insert) valueExprs(dialect Dialect) []string {
+	var (
+		columnNames = ins.columnNames()
+		tags        = ins.columnTags()
+		exprs       []string
+	)
+	for i, columnName := range columnNames {
+		expr, ok := ins.options().exprs[columnName]
+		if !ok && tags != nil {
+			if expr = tags[i].opts[`expr`]; expr == `` {
+				if cast := tags[i].cast(dialect); cast != `` {
+					expr = `?` + cast
+				}
+			}
+		}
+		if expr == `` {
+			continue
+		}
+		if exprs == nil {
+			exprs = make([]string, len(columnNames))
+		}
+		exprs[i] = expr
+	}
+	return exprs
+}
+
+// checkExprs returns the error of an unknown column in Expr, or of a value expression with more than one
+// placeholder.
+func (ins *Insert) checkExprs(dialect Dialect) error {
+	for column := range ins.options().exprs {
+		if indexOf(ins.allColumnNames(), column) < 0 {
+			return fmt.Errorf(`sqlinsert: unknown column %q`, column)
+		}
+	}
+	for i, expr := range ins.valueExprs(dialect) {
+		if n := len(placeholders(expr)); n > 1 {
+			return fmt.Errorf(`sqlinsert: column %s: expression %q has %d placeholders, expected at most 1`,
+				ins.columnNames()[i], expr, n)
+		}
+	}
+	return nil
+}
+
+// placeholders returns the indexes of the ? placeholders in expr that are outside single-quoted strings and
+// double-quoted identifiers.
+func placeholders(expr string) []int {
+	var (
+		indexes []int
+		quote   byte
+	)
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// takesArg reports whether a value expression binds the value of its column: it is empty or has a placeholder.
+func takesArg(expr string) bool {
+	return expr == `` || len(placeholders(expr)) > 0
+}
+
+// substitute returns expr with its placeholder replaced by token, or token if expr is empty.
+func substitute(expr string, token string) string {
+	if expr == `` {
+		return token
+	}
+	indexes := placeholders(expr)
+	if len(indexes) == 0 {
+		return expr
+	}
+	return expr[:indexes[0]] + token + expr[indexes[0]+1:]
+}
+
+// validate returns the error of Insert.Data that is not rows of one struct type (see checkData), of an unknown
+// column in Only, Exclude, or Expr, of an invalid value expression, or of a statement form that the dialect does
+// not support.
+func (ins *Insert) validate(dialect Dialect) error {
+	if err := ins.checkData(); err != nil {
+		return err
+	}
+	if err := ins.checkColumns(); err != nil {
+		return err
+	}
+	if err := ins.checkExprs(dialect); err != nil {
+		return err
+	}
+	return ins.checkStatement(dialect)
+}
+
+// exprOf returns the value expression of column i, or an empty string if there is none.
+func exprOf(exprs []string, i int) string {
+	if exprs == nil {
+		return ``
+	}
+	return exprs[i]
+}
@@ -0,0 +1,99 @@
+package sqlinsert
+
+import (
+	"reflect"
+	"testing"
+)
+
+type candyExpr struct {
+	ID        string  `col:"id,expr=gen_random_uuid()"`
+	Name      string  `col:"candy_name,expr=?::citext"`
+	Location  string  `col:"location,expr=ST_GeomFromText(?, 4326)"`
+	CreatedAt string  `col:"created_at,expr=NOW()"`
+	Weight    float64 `col:"weight_grams"`
+}
+
+var candyExprRecs = []candyExpr{
+	{`ignored`, `Gougat`, `POINT(1 2)`, `ignored`, 1.5},
+	{`ignored`, `Toffee`, `POINT(3 4)`, `ignored`, 2.5},
+}
+
+/* Expressions */
+
+func TestParamsExprTag(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	ins := Insert{Table: tbl, Data: candyExprRecs}
+	expected := `(gen_random_uuid(),$1::citext,ST_GeomFromText($2, 4326),NOW(),$3),` +
+		`(gen_random_uuid(),$4::citext,ST_GeomFromText($5, 4326),NOW(),$6)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+}
+
+func TestArgsExprTag(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyExprRecs}
+	expected := []interface{}{`Gougat`, `POINT(1 2)`, 1.5, `Toffee`, `POINT(3 4)`, 2.5}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestParamsExprOption(t *testing.T) {
+	UseTokenType = AtColumnNameTokenType
+	ins := NewInsert(tbl, recValue, Expr(`ts`, `SYSDATETIME()`), Expr(`candy_name`, `UPPER(?)`))
+	expected := `(@id,UPPER(@candy_name),@form_factor,@description,@manufacturer,@weight_grams,SYSDATETIME())`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+	if args := ins.Args(); len(args) != 6 {
+		t.Fatalf(`expected 6 args, got %d`, len(args))
+	}
+}
+
+func TestParamsExprOptionOverridesTag(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, candyExprRecs[0], Expr(`id`, `?`), Expr(`created_at`, `'?'`))
+	expected := `(?,?::citext,ST_GeomFromText(?, 4326),'?',?)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+	expectedArgs := []interface{}{`ignored`, `Gougat`, `POINT(1 2)`, 1.5}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestParamsExprRowsDefault(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	ins := NewInsert(tbl, Rows{Columns: []string{`id`, `ts`}, Values: [][]interface{}{{`a`, nil}, {`b`, Default}}},
+		Expr(`ts`, `COALESCE(?, NOW())`))
+	expected := `($1,COALESCE($2, NOW())),($3,DEFAULT)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+}
+
+func TestDebugSQLExpr(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyExprRecs[0]}
+	expected := `INSERT INTO candy (id,candy_name,location,created_at,weight_grams) VALUES ` +
+		`(gen_random_uuid(),'Gougat'::citext,ST_GeomFromText('POINT(1 2)', 4326),NOW(),1.5)`
+	debugSQL := ins.DebugSQL(PostgresDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+func TestBuildExprErrors(t *testing.T) {
+	for _, opt := range []Option{Expr(`nope`, `NOW()`), Expr(`id`, `? + ?`)} {
+		ins := NewInsert(tbl, recValue, opt)
+		if _, _, err := ins.Build(); err == nil {
+			t.Fatal(`expected error from Expr`)
+		}
+	}
+}
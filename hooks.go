@@ -0,0 +1,79 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+)
+
+// BeforeInserter is implemented by row types that prepare themselves for insertion, e.g., to set an ID or to
+// normalize strings. Insert.Insert and Insert.InsertContext call BeforeInsert once per row before the bind args are
+// collected. An error aborts the insert before any SQL is sent.
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInserter is implemented by row types that act on their own insertion, e.g., to publish a domain event.
+// Insert.Insert and Insert.InsertContext call AfterInsert once per row after the INSERT executes successfully, with
+// the result of the statement that inserted the row.
+type AfterInserter interface {
+	AfterInsert(ctx context.Context, result sql.Result) error
+}
+
+// rows returns the rows of Insert.Data. Rows held in a slice of struct values are returned as pointers into the
+// slice, so that hooks with pointer receivers are found and may modify the row. A lone struct value is not
+// addressable, so only hooks with value receivers are found on it, nor is a struct value held in a slice of
+// interfaces. Rows yields the values of each row.
+func (ins *Insert) rows() []reflect.Value {
+	if rows, ok := asRows(ins.Data); ok {
+		values := make([]reflect.Value, len(rows.Values))
+		for i := range values {
+			values[i] = reflect.ValueOf(rows.Values[i])
+		}
+		return values
+	}
+	data := reflect.ValueOf(ins.Data)
+	if data.Kind() != reflect.Slice {
+		return []reflect.Value{data}
+	}
+	rows := make([]reflect.Value, data.Len())
+	for i := range rows {
+		row := data.Index(i)
+		if row.Kind() == reflect.Interface {
+			row = row.Elem()
+		}
+		if row.Kind() == reflect.Struct && row.CanAddr() {
+			row = row.Addr()
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// beforeInsert calls BeforeInsert on each row that implements BeforeInserter, stopping at the first error.
+func beforeInsert(ctx context.Context, rows []reflect.Value) error {
+	for _, row := range rows {
+		if hook, ok := row.Interface().(BeforeInserter); ok {
+			if err := hook.BeforeInsert(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// afterInsert calls AfterInsert with the result of the statement that inserted it on each row that implements
+// AfterInserter, stopping at the first error. Rows without a result, which failed to insert, are left out.
+func afterInsert(ctx context.Context, rows []reflect.Value, results []sql.Result) error {
+	for i, row := range rows {
+		if results[i] == nil {
+			continue
+		}
+		if hook, ok := row.Interface().(AfterInserter); ok {
+			if err := hook.AfterInsert(ctx, results[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// hookedInsert sets its id and normalizes its name before insert, and counts its inserts by id after.
+type hookedInsert struct {
+	Id   string `col:"id"`
+	Name string `col:"candy_name"`
+}
+
+var hookedInserts = map[string]int{}
+
+func (rec *hookedInsert) BeforeInsert(ctx context.Context) error {
+	if rec.Name == `` {
+		return errors.New(`candy_name is required`)
+	}
+	rec.Id = `id-` + rec.Name
+	rec.Name = strings.ToUpper(rec.Name)
+	return nil
+}
+
+func (rec *hookedInsert) AfterInsert(ctx context.Context, result sql.Result) error {
+	hookedInserts[rec.Id]++
+	return nil
+}
+
+// resultHookedInsert keeps the last insert ID of the result that AfterInsert receives by id.
+type resultHookedInsert struct {
+	Id string `col:"id"`
+}
+
+var lastInsertIds = map[string]int64{}
+
+func (rec *resultHookedInsert) AfterInsert(ctx context.Context, result sql.Result) error {
+	id, err := result.LastInsertId()
+	lastInsertIds[rec.Id] = id
+	return err
+}
+
+/* BeforeInsert, AfterInsert */
+
+// TestInsertContextHooksManyRecsValues tests that hooks with pointer receivers run per row on slice-of-struct input
+func TestInsertContextHooksManyRecsValues(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	recs := []hookedInsert{{Name: `gougat`}, {Name: `gumdrop`}}
+	ins := Insert{Table: tbl, Data: recs}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?),(?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).
+		WithArgs(`id-gougat`, `GOUGAT`, `id-gumdrop`, `GUMDROP`).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	_, err = ins.InsertContext(context.Background(), db)
+	if err != nil {
+		t.Fatalf(`failed at InsertContext, could not execute SQL statement %s`, err)
+	}
+	for _, rec := range recs {
+		if hookedInserts[rec.Id] != 1 {
+			t.Fatalf(`expected AfterInsert once for %s, got %d`, rec.Id, hookedInserts[rec.Id])
+		}
+	}
+}
+
+// TestInsertHooksOneRecPointer tests that hooks run on struct-pointer input
+func TestInsertHooksOneRecPointer(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rec := &hookedInsert{Name: `toffee`}
+	ins := Insert{Table: tbl, Data: rec}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`id-toffee`, `TOFFEE`).WillReturnResult(sqlmock.NewResult(1, 1))
+	_, err = ins.Insert(db)
+	if err != nil {
+		t.Fatalf(`failed at Insert, could not execute SQL statement %s`, err)
+	}
+	if hookedInserts[rec.Id] != 1 {
+		t.Fatalf(`expected AfterInsert once, got %d`, hookedInserts[rec.Id])
+	}
+}
+
+// TestInsertContextBeforeInsertError tests that a BeforeInsert error aborts the insert before any SQL is sent
+func TestInsertContextBeforeInsertError(t *testing.T) {
+	recs := []*hookedInsert{{Name: `caramel`}, {Name: ``}}
+	ins := Insert{Table: tbl, Data: recs}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	_, err = ins.InsertContext(context.Background(), db)
+	if err == nil {
+		t.Fatal(`expected error from BeforeInsert`)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`expected no SQL, got %s`, err)
+	}
+	if hookedInserts[recs[0].Id] != 0 {
+		t.Fatalf(`expected no AfterInsert, got %d`, hookedInserts[recs[0].Id])
+	}
+}
+
+// TestInsertContextAfterInsertPerStatement tests that AfterInsert receives the result of the statement of its row
+func TestInsertContextAfterInsertPerStatement(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	recs := []resultHookedInsert{{Id: `a`}, {Id: `b`}, {Id: `bad`}}
+	ins := NewInsert(tbl, recs, IsolateErrors())
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?),(?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnError(errors.New(`constraint violated`))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`).WillReturnResult(sqlmock.NewResult(10, 1))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`b`, `bad`).WillReturnError(errors.New(`constraint violated`))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`b`).WillReturnResult(sqlmock.NewResult(20, 1))
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`bad`).WillReturnError(errors.New(`constraint violated`))
+	if _, err = ins.InsertContext(context.Background(), db); err == nil {
+		t.Fatal(`expected RowErrors`)
+	}
+	for id, expected := range map[string]int64{`a`: 10, `b`: 20} {
+		if lastInsertIds[id] != expected {
+			t.Fatalf(`expected last insert ID %d for %s, got %d`, expected, id, lastInsertIds[id])
+		}
+	}
+	if _, ok := lastInsertIds[`bad`]; ok {
+		t.Fatal(`expected no AfterInsert for the failing row`)
+	}
+}
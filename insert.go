@@ -30,135 +30,469 @@ type Inserter interface {
 
 // Insert models data used to produce a valid SQL INSERT statement with bind args.
 // Table is the table name. Data is either a struct with column-name tagged fields and the data to be inserted or
-// a slice struct (struct ptr works too).
+// a slice struct (struct ptr works too, as does []interface{} of rows of one struct type), or Rows or Maps for data
+// without a struct. Options, such as Only, Exclude, and WithDialect, are set by NewInsert or With.
 type Insert struct {
 	Table string
 	Data  interface{}
+	opts  *insertOptions
 }
 
 // Columns returns the comma-separated list of column names-as-tokens for the SQL INSERT statement.
-// Multi Row Insert: Insert.Data is a slice; first item in slice is
+// Multi-row INSERT: Insert.Data is a slice of rows of one struct type, whose columns are those of every row.
+// Columns, Params, SQL, and Args do not check the Insert: Build returns the errors that they leave out.
 func (ins *Insert) Columns() string {
-	v := reflect.ValueOf(ins.Data)
-	if v.Kind() == reflect.Slice {
-		if v.Index(0).Kind() == reflect.Pointer {
-			return Tokenize(v.Index(0).Elem().Type(), ColumnNameTokenType)
-		} else {
-			return Tokenize(v.Index(0).Type(), ColumnNameTokenType)
-		}
-	} else if v.Kind() == reflect.Pointer {
-		return Tokenize(v.Elem().Type(), ColumnNameTokenType)
-	} else {
-		return Tokenize(v.Type(), ColumnNameTokenType)
-	}
+	return tokenize(ins.resolved().columnNames(), ColumnNameTokenType, 0)
 }
 
 // Params returns the comma-separated list of bind param tokens for the SQL INSERT statement.
+// Multi-row INSERT: one list per row, numbered in sequence across rows for OrdinalNumberTokenType and
+// AtPOrdinalTokenType, and with names suffixed with the number of the row for the named token types, e.g.,
+// (@foo_1,@bar_1),(@foo_2,@bar_2).
+// Columns with a value expression (see Expr) have it in place of the bind param, e.g., NOW() or ST_GeomFromText($3),
+// and in PostgresDialect (or CockroachDialect), the bind params of fields tagged `json=jsonb` (or `json=json`) are
+// cast, e.g., $3::jsonb.
 func (ins *Insert) Params() string {
-	v := reflect.ValueOf(ins.Data)
-	if v.Kind() == reflect.Slice {
-		var (
-			b        strings.Builder
-			paramRow string
-		)
-		if v.Index(0).Kind() == reflect.Pointer {
-			paramRow = Tokenize(v.Index(0).Elem().Type(), UseTokenType)
-		} else {
-			paramRow = Tokenize(v.Index(0).Type(), UseTokenType)
-		}
-		b.WriteString(paramRow)
-		for i := 1; i < v.Len(); i++ {
-			b.WriteString(`,`)
-			b.WriteString(paramRow)
-		}
-		return b.String()
-	} else if v.Kind() == reflect.Pointer {
-		return Tokenize(v.Elem().Type(), UseTokenType)
-	} else {
-		return Tokenize(v.Type(), UseTokenType)
+	rows := ins.resolved().paramRows()
+	lists := make([]string, len(rows))
+	for i, tokens := range rows {
+		lists[i] = `(` + strings.Join(tokens, `,`) + `)`
 	}
+	return strings.Join(lists, `,`)
 }
 
-// SQL returns the full parameterized SQL INSERT statement.
+// paramRows returns the bind param tokens of each row, in column order, numbered in sequence across rows.
+func (ins *Insert) paramRows() [][]string {
+	var (
+		columnNames = ins.columnNames()
+		exprs       = ins.valueExprs(ins.options().dialect)
+		rowValues   = ins.rowValues()
+		rows        = make([][]string, len(rowValues))
+		numParams   int
+	)
+	for i, values := range rowValues {
+		row := 0
+		if len(rowValues) > 1 {
+			row = i + 1
+		}
+		var n int
+		rows[i], n = rowTokens(columnNames, values, exprs, ins.tokenType(), numParams, row)
+		numParams += n
+	}
+	return rows
+}
+
+// SQL returns the full parameterized SQL INSERT statement, in the form that the options and WithDialect call
+// for, e.g., INSERT IGNORE INTO for IgnoreConflicts in MySQL. An insert of no columns, which DefaultGroups returns
+// for a row whose columns are all Default, inserts DEFAULT VALUES. A multi-row insert in OracleDialect is an INSERT
+// ALL ... SELECT 1 FROM DUAL, since Oracle has no multi-row VALUES. SQL does not check that the dialect supports
+// the form, e.g., Upsert in MySQL; Build returns the error.
 func (ins *Insert) SQL() string {
-	var insertSQL strings.Builder
-	_, _ = fmt.Fprintf(&insertSQL, `INSERT INTO %s %s VALUES %s`,
-		ins.Table, ins.Columns(), ins.Params())
-	return insertSQL.String()
+	ins = ins.resolved()
+	return ins.statement(ins.options().dialect, ins.paramRows())
 }
 
-// Args returns the arguments to be bound in Insert() or the variadic Exec/ExecContext functions in database/sql.
-func (ins *Insert) Args() []interface{} {
+// statement returns the SQL INSERT statement of the rows of values (bind params or literals) in the dialect.
+func (ins *Insert) statement(dialect Dialect, rows [][]string) string {
+	columnNames := ins.columnNames()
+	if ins.options().ignoreConflicts && (dialect == SQLServerDialect || dialect == OracleDialect) {
+		return ins.mergeStatement(dialect, columnNames, rows)
+	}
+	if dialect == OracleDialect && len(rows) > 1 && len(columnNames) > 0 {
+		return ins.insertAllStatement(columnNames, rows)
+	}
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, `%s %s `, ins.verb(dialect), ins.Table)
+	if len(columnNames) == 0 {
+		b.WriteString(`DEFAULT VALUES`)
+	} else {
+		_, _ = fmt.Fprintf(&b, `%s VALUES `, tokenize(columnNames, ColumnNameTokenType, 0))
+		for i, values := range rows {
+			if i > 0 {
+				b.WriteString(`,`)
+			}
+			_, _ = fmt.Fprintf(&b, `(%s)`, strings.Join(values, `,`))
+		}
+	}
+	b.WriteString(ins.onConflict(dialect))
+	return b.String()
+}
+
+// insertAllStatement returns the Oracle INSERT ALL statement of the rows of values, since Oracle has no multi-row
+// VALUES.
+func (ins *Insert) insertAllStatement(columnNames []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString(`INSERT ALL`)
+	columns := tokenize(columnNames, ColumnNameTokenType, 0)
+	for _, values := range rows {
+		_, _ = fmt.Fprintf(&b, ` INTO %s %s VALUES (%s)`, ins.Table, columns, strings.Join(values, `,`))
+	}
+	b.WriteString(` SELECT 1 FROM DUAL`)
+	return b.String()
+}
+
+// DebugSQL returns the SQL INSERT statement with the bind args of Args in place of the bind params, as literals
+// of the dialect, e.g., to paste into a SQL console to reproduce a failed insert.
+// DebugSQL is for debugging ONLY. Never execute its output: unlike bind args, the literals are not safe from SQL
+// injection. A bind arg that has no SQL literal is written as a comment, which leaves the statement invalid.
+func (ins *Insert) DebugSQL(dialect Dialect) string {
+	ins = ins.resolved()
+	if err := ins.validate(dialect); err != nil {
+		return `/* ` + err.Error() + ` */`
+	}
+	debugSQL, _ := ins.literalSQL(dialect, false)
+	return debugSQL
+}
+
+// literalSQL returns the SQL INSERT statement with the bind args as literals of the dialect. If strict, a bind arg
+// that fails to convert or has no SQL literal is a *ConvertError, otherwise it is written as a comment.
+func (ins *Insert) literalSQL(dialect Dialect, strict bool) (string, error) {
+	if err := ins.validate(dialect); err != nil {
+		return ``, err
+	}
 	var (
-		data    reflect.Value
-		rec     reflect.Value
-		recType reflect.Type
-		args    []interface{}
+		columnNames = ins.columnNames()
+		tags        = ins.columnTags()
+		fieldNames  = ins.fieldNames()
+		exprs       = ins.valueExprs(dialect)
+		rowValues   = ins.rowValues()
+		rows        = make([][]string, len(rowValues))
 	)
-	data = reflect.ValueOf(ins.Data)
-	if data.Kind() == reflect.Slice { // Multi row INSERT: Insert.Data is a slice-of-struct-pointer or slice-of-struct
-		argIndex := -1
-		if data.Index(0).Kind() == reflect.Pointer { // First slice element is struct pointers
-			recType = data.Index(0).Elem().Type()
-		} else { // First slice element is struct
-			recType = data.Index(0).Type()
-		}
-		numRecs := data.Len()
-		numFieldsPerRec := recType.NumField()
-		numBindArgs := numRecs * numFieldsPerRec
-		args = make([]interface{}, numBindArgs)
-		for rowIndex := 0; rowIndex < data.Len(); rowIndex++ {
-			if data.Index(0).Kind() == reflect.Pointer {
-				rec = data.Index(rowIndex).Elem() // Cur slice elem is struct pointer, get arg val from ref-element
-			} else {
-				rec = data.Index(rowIndex) // Cur slice elem is struct, can get arg val directly
+	for i, values := range rowValues {
+		rows[i] = make([]string, len(values))
+		for j, value := range values {
+			expr := exprOf(exprs, j)
+			if isDefault(value) {
+				rows[i][j] = `DEFAULT`
+				continue
+			}
+			if !takesArg(expr) {
+				rows[i][j] = expr
+				continue
 			}
-			for fieldIndex := 0; fieldIndex < numFieldsPerRec; fieldIndex++ {
-				argIndex += 1
-				args[argIndex] = rec.Field(fieldIndex).Interface()
+			var tag columnTag
+			if tags != nil {
+				tag = tags[j]
 			}
+			lit := ``
+			value, err := bindValue(dialect, tag, value)
+			if err == nil {
+				lit, err = literal(dialect, value)
+			}
+			if err != nil {
+				if strict {
+					return ``, ins.convertError(i, j, columnNames, fieldNames, err)
+				}
+				lit = `/* ` + err.Error() + ` */`
+			}
+			rows[i][j] = substitute(expr, lit)
 		}
-		return args
-	} else { // Single-row INSERT: Insert.Data must be a struct pointer or struct (otherwise reflect will panic)
-		if data.Kind() == reflect.Pointer { // Row information via struct pointer
-			recType = data.Elem().Type()
-			rec = data.Elem()
-		} else { // Row information via struct
-			recType = data.Type()
-			rec = data
+	}
+	return ins.statement(dialect, rows), nil
+}
+
+// Batches splits a multi-row insert into inserts of at most size rows each, in order, for executing (or writing)
+// a large slice as several statements. A single-row insert, or a size less than 1, yields the insert itself.
+func (ins *Insert) Batches(size int) []Insert {
+	if rows, ok := asRows(ins.Data); ok {
+		if size < 1 || len(rows.Values) <= size {
+			return []Insert{*ins}
 		}
-		args = make([]interface{}, recType.NumField())
-		for i := 0; i < recType.NumField(); i++ {
-			args[i] = rec.Field(i).Interface()
+		batches := make([]Insert, 0, (len(rows.Values)+size-1)/size)
+		for i := 0; i < len(rows.Values); i += size {
+			j := i + size
+			if j > len(rows.Values) {
+				j = len(rows.Values)
+			}
+			batches = append(batches, ins.derive(&Rows{Columns: rows.Columns, Values: rows.Values[i:j]},
+				ins.rowRange(i, j)))
 		}
-		return args
+		return batches
 	}
+	data := reflect.ValueOf(ins.Data)
+	if data.Kind() != reflect.Slice || size < 1 || data.Len() <= size {
+		return []Insert{*ins}
+	}
+	batches := make([]Insert, 0, (data.Len()+size-1)/size)
+	for i := 0; i < data.Len(); i += size {
+		j := i + size
+		if j > data.Len() {
+			j = data.Len()
+		}
+		batches = append(batches, ins.derive(data.Slice(i, j).Interface(), ins.rowRange(i, j)))
+	}
+	return batches
 }
 
-// Insert prepares and executes a SQL INSERT statement on a *sql.DB, *sql.Tx,
-// or other Inserter-compatible interface to Prepare and Exec.
-func (ins *Insert) Insert(with InsertWith) (*sql.Stmt, error) {
-	stmt, err := with.Prepare(ins.SQL())
+// rowRange returns the row numbers of rows i to j (exclusive) of Insert.Data (see rowNumber).
+func (ins *Insert) rowRange(i, j int) []int {
+	rowIndex := make([]int, j-i)
+	for k := range rowIndex {
+		rowIndex[k] = ins.rowNumber(i + k)
+	}
+	return rowIndex
+}
+
+// Args returns the arguments to be bound in Insert() or the variadic Exec/ExecContext functions in database/sql.
+// Multi-row INSERT: the args of each row in turn. Default values have no args. Values of a type with a registered
+// Converter are converted. Args returns nil if Build returns an error, e.g., the *ConvertError of a failed
+// Converter or the error of a nil row.
+func (ins *Insert) Args() []interface{} {
+	args, _ := ins.resolved().checkedArgs(false)
+	return args
+}
+
+// NamedArgs returns Args as sql.NamedArg values named after their columns, for the named token types
+// AtColumnNameTokenType and ColonTokenType, which drivers such as go-mssqldb and godror bind by name.
+// Multi-row INSERT: the names are suffixed with the number of the row, e.g., foo_1, foo_2, as their tokens are.
+// Like Args, it returns nil if Build returns an error.
+func (ins *Insert) NamedArgs() []interface{} {
+	args, _ := ins.resolved().checkedArgs(true)
+	return args
+}
+
+// Build returns SQL() and the args to bind it: Args(), or NamedArgs() if the token type of the Insert (see
+// WithDialect) is a named token type.
+// It returns the error of Insert.Data that is not rows of one struct type, e.g., a nil row, of an unknown column
+// in Only, Exclude, or Expr, of an invalid value expression, or of a statement form that the dialect does not
+// support, or the *ConvertError of a failed Converter.
+func (ins *Insert) Build() (string, []interface{}, error) {
+	ins = ins.resolved()
+	if err := ins.validate(ins.options().dialect); err != nil {
+		return ``, nil, err
+	}
+	return ins.build()
+}
+
+// build is Build for an Insert that is valid.
+func (ins *Insert) build() (string, []interface{}, error) {
+	args, err := ins.args(ins.tokenType().named())
 	if err != nil {
+		return ``, nil, err
+	}
+	return ins.SQL(), args, nil
+}
+
+// checkedArgs returns the bind args of a valid Insert, or the error of Build.
+func (ins *Insert) checkedArgs(named bool) ([]interface{}, error) {
+	if err := ins.validate(ins.options().dialect); err != nil {
 		return nil, err
 	}
-	defer func(stmt *sql.Stmt) {
-		_ = stmt.Close()
-	}(stmt)
-	_, err = stmt.Exec(ins.Args()...)
-	return stmt, err
+	return ins.args(named)
 }
 
-// InsertContext prepares and executes a SQL INSERT statement on a *sql.DB, *sql.Tx, *sql.Conn,
+// args returns the bind args of each row in turn, leaving out Default values and the values of columns whose
+// value expressions take no bind arg. If named, they are sql.NamedArg values named as their tokens.
+func (ins *Insert) args(named bool) ([]interface{}, error) {
+	exprs := ins.valueExprs(ins.options().dialect)
+	bindValues, err := ins.bindValues(exprs)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		columnNames = ins.columnNames()
+		args        = make([]interface{}, 0, len(bindValues)*len(columnNames))
+	)
+	for i, values := range bindValues {
+		for j, value := range values {
+			if isDefault(value) || !takesArg(exprOf(exprs, j)) {
+				continue
+			}
+			if named {
+				row := 0
+				if len(bindValues) > 1 {
+					row = i + 1
+				}
+				value = sql.Named(paramName(columnNames[j], row), value)
+			}
+			args = append(args, value)
+		}
+	}
+	return args, nil
+}
+
+// Insert prepares and executes a SQL INSERT statement on a *sql.DB, *sql.Tx, StmtCache,
+// or other Inserter-compatible interface to Prepare and Exec.
+// It is InsertContext with context.Background().
+func (ins *Insert) Insert(with InsertWith) (*sql.Stmt, error) {
+	return ins.InsertContext(context.Background(), with)
+}
+
+// InsertContext prepares and executes a SQL INSERT statement on a *sql.DB, *sql.Tx, *sql.Conn, StmtCache,
 // or other Inserter-compatible interface to PrepareContext and ExecContext.
+// Rows that implement BeforeInserter have BeforeInsert called before the SQL is prepared,
+// and rows that implement AfterInserter have AfterInsert called after it executes successfully.
+// If the dialect has no DEFAULT keyword in VALUES, the DefaultGroups are executed in turn, and the statement of the
+// last one is returned. Under IsolateErrors, failing rows are left out and reported by the RowErrors returned, and
+// AfterInsert is called on the rows inserted. AfterInsert receives the result of the statement that inserted the
+// row, which is one of several if the insert runs as several statements. If with is a StmtCache, the statement
+// belongs to the cache, which may close it, and nil is returned in its place.
 func (ins *Insert) InsertContext(ctx context.Context, with InsertWith) (*sql.Stmt, error) {
-	stmt, err := with.Prepare(ins.SQL())
+	stmt, _, err := ins.insert(ctx, with)
+	return callerStmt(with, stmt), err
+}
+
+// insert is InsertContext, also returning the InsertResult.
+func (ins *Insert) insert(ctx context.Context, with InsertWith) (*sql.Stmt, *InsertResult, error) {
+	ins = ins.resolved()
+	if ins.options().groupTypes {
+		return ins.insertTypeGroups(ctx, with)
+	}
+	if err := ins.checkData(); err != nil {
+		return nil, nil, err
+	}
+	rows := ins.rows()
+	if err := beforeInsert(ctx, rows); err != nil {
+		return nil, nil, err
+	}
+	var (
+		stmt  *sql.Stmt
+		err   error
+		total = &InsertResult{Rows: len(rows)}
+	)
+	for _, group := range ins.DefaultGroups(ins.options().dialect) {
+		var (
+			groupStmt   *sql.Stmt
+			groupResult sql.Result
+		)
+		if ins.isolates() {
+			groupStmt, groupResult, err = group.execIsolated(ctx, with, total)
+		} else if ins.options().deadLetters != nil {
+			// In a *sql.Tx, the failing statement is rolled back to a savepoint, which keeps the transaction usable
+			if groupStmt, groupResult, err = group.execSavepoint(ctx, with); err == nil {
+				total.addStatement(&group, groupResult)
+			} else if _, ok := err.(*savepointError); !ok && ctx.Err() == nil {
+				total.Failed = append(total.Failed, group.rowErrors(err)...)
+				err = nil
+			}
+		} else if groupStmt, groupResult, err = group.exec(ctx, with); err == nil {
+			total.addStatement(&group, groupResult)
+		}
+		if err != nil {
+			return groupStmt, nil, err
+		}
+		if groupResult != nil {
+			stmt = groupStmt
+		}
+	}
+	total.Skipped = -1
+	if total.Inserted >= 0 && ins.options().statementVerb == `` {
+		if total.Skipped = int64(total.Rows-len(total.Failed)) - total.Inserted; total.Skipped < 0 {
+			total.Skipped = 0
+		}
+	}
+	results := make([]sql.Result, len(rows))
+	for i := range rows {
+		results[i] = total.results[ins.rowNumber(i)]
+	}
+	if err = afterInsert(ctx, rows, results); err != nil || len(total.Failed) == 0 {
+		return stmt, total, err
+	}
+	if ins.options().deadLetters != nil {
+		return stmt, total, ins.options().deadLetters.send(ctx, ins, total.Failed)
+	}
+	return stmt, total, RowErrors(total.Failed)
+}
+
+// exec builds, prepares, and executes the SQL INSERT statement, observed by the Observer.
+func (ins *Insert) exec(ctx context.Context, with InsertWith) (*sql.Stmt, sql.Result, error) {
+	query, args, err := ins.Build()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	ctx, finish := observe(ctx, ins.options().observer, query, args, len(ins.rows()))
+	stmt, done, err := prepare(ctx, with, query)
+	if err != nil {
+		finish(nil, err)
+		return nil, nil, err
+	}
+	result, err := stmt.ExecContext(ctx, args...)
+	done(err)
+	finish(result, err)
+	return stmt, result, err
+}
+
+// recordType returns the struct type of a row of data, which is a struct, a struct pointer, or a slice of either,
+// or of interfaces holding them, whose type is that of the first non-nil row.
+func recordType(data interface{}) reflect.Type {
+	t := reflect.TypeOf(data)
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+		if t.Kind() == reflect.Interface {
+			rows := reflect.ValueOf(data)
+			for i := 0; i < rows.Len(); i++ {
+				if rowType, err := structType(rows.Index(i), i); err == nil {
+					return rowType
+				}
+			}
+			return t
+		}
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// columnNames returns the column names of Insert.Data that remain after Only and Exclude, in order.
+func (ins *Insert) columnNames() []string {
+	indexes := ins.columnIndexes()
+	return selectStrings(ins.allColumnNames(), indexes)
+}
+
+// allColumnNames returns the column names of Insert.Data, in order.
+func (ins *Insert) allColumnNames() []string {
+	if rows, ok := asRows(ins.Data); ok {
+		return rows.Columns
+	}
+	return fieldColumnNames(recordType(ins.Data))
+}
+
+// rowValues returns the values of each row of Insert.Data, in column order, of the columns that remain after Only
+// and Exclude. Zero values are Default for DefaultZero and fields with the defaultzero tag option. A row that is
+// not of the struct type of the first row, e.g., a nil row, has nil values (see checkData).
+func (ins *Insert) rowValues() [][]interface{} {
+	indexes := ins.columnIndexes()
+	if rows, ok := asRows(ins.Data); ok {
+		if indexes == nil && !ins.options().defaultZero && !rows.ragged() {
+			return rows.Values
+		}
+		rowValues := make([][]interface{}, len(rows.Values))
+		for i, values := range rows.Values {
+			if len(values) != len(rows.Columns) { // Invalid (see checkData): pad with nil or cut to the columns
+				values = append(values[:len(values):len(values)], make([]interface{}, len(rows.Columns))...)
+				values = values[:len(rows.Columns)]
+			}
+			if ins.options().defaultZero {
+				values = defaultZeros(values)
+			}
+			rowValues[i] = selectValues(values, indexes)
+		}
+		return rowValues
+	}
+	t := recordType(ins.Data)
+	zeroDefaults := make([]bool, t.NumField())
+	for i := range zeroDefaults {
+		zeroDefaults[i] = ins.options().defaultZero || parseTag(t.Field(i)).has(`defaultzero`)
+	}
+	records := ins.rows()
+	rowValues := make([][]interface{}, len(records))
+	for i, rec := range records {
+		rec = reflect.Indirect(rec) // Row information via struct pointer or struct
+		values := make([]interface{}, t.NumField())
+		if !rec.IsValid() || rec.Type() != t {
+			rowValues[i] = selectValues(values, indexes)
+			continue
+		}
+		for fieldIndex := range values {
+			field := rec.Field(fieldIndex)
+			if zeroDefaults[fieldIndex] && field.IsZero() {
+				values[fieldIndex] = Default
+			} else {
+				values[fieldIndex] = field.Interface()
+			}
+		}
+		rowValues[i] = selectValues(values, indexes)
 	}
-	defer func(stmt *sql.Stmt) {
-		_ = stmt.Close()
-	}(stmt)
-	_, err = stmt.ExecContext(ctx, ins.Args()...)
-	return stmt, err
+	return rowValues
 }
@@ -14,7 +14,7 @@ import (
 // - Single-row Insert.Columns
 
 func TestColumnsOneRecValue(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `(id,candy_name,form_factor,description,manufacturer,weight_grams,ts)`
 	columns := ins.Columns()
 	if expected != columns {
@@ -23,7 +23,7 @@ func TestColumnsOneRecValue(t *testing.T) {
 }
 
 func TestColumnsOneRecPointer(t *testing.T) {
-	ins := Insert{tbl, recPointer}
+	ins := Insert{Table: tbl, Data: recPointer}
 	expected := `(id,candy_name,form_factor,description,manufacturer,weight_grams,ts)`
 	columns := ins.Columns()
 	if expected != columns {
@@ -34,7 +34,7 @@ func TestColumnsOneRecPointer(t *testing.T) {
 // - Multi-row Insert.Columns
 
 func TestColumnsManyRecsValues(t *testing.T) {
-	ins := Insert{tbl, fiveRecsValues}
+	ins := Insert{Table: tbl, Data: fiveRecsValues}
 	expected := `(id,candy_name,form_factor,description,manufacturer,weight_grams,ts)`
 	columns := ins.Columns()
 	if expected != columns {
@@ -43,7 +43,7 @@ func TestColumnsManyRecsValues(t *testing.T) {
 }
 
 func TestColumnsManyRecsPointers(t *testing.T) {
-	ins := Insert{tbl, fiveRecsPointers}
+	ins := Insert{Table: tbl, Data: fiveRecsPointers}
 	expected := `(id,candy_name,form_factor,description,manufacturer,weight_grams,ts)`
 	columns := ins.Columns()
 	if expected != columns {
@@ -57,7 +57,7 @@ func TestColumnsManyRecsPointers(t *testing.T) {
 
 func TestParamsOneRecValue(t *testing.T) {
 	UseTokenType = QuestionMarkTokenType
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `(?,?,?,?,?,?,?)`
 	params := ins.Params()
 	if expected != params {
@@ -67,7 +67,7 @@ func TestParamsOneRecValue(t *testing.T) {
 
 func TestParamsOneRecPointer(t *testing.T) {
 	UseTokenType = QuestionMarkTokenType
-	ins := Insert{tbl, recPointer}
+	ins := Insert{Table: tbl, Data: recPointer}
 	expected := `(?,?,?,?,?,?,?)`
 	params := ins.Params()
 	if expected != params {
@@ -79,7 +79,7 @@ func TestParamsOneRecPointer(t *testing.T) {
 
 func TestParamsManyRecsValues(t *testing.T) {
 	UseTokenType = QuestionMarkTokenType
-	ins := Insert{tbl, fiveRecsValues}
+	ins := Insert{Table: tbl, Data: fiveRecsValues}
 	expected := `(?,?,?,?,?,?,?),(?,?,?,?,?,?,?),(?,?,?,?,?,?,?),(?,?,?,?,?,?,?),(?,?,?,?,?,?,?)`
 	params := ins.Params()
 	if expected != params {
@@ -89,7 +89,7 @@ func TestParamsManyRecsValues(t *testing.T) {
 
 func TestParamsManyRecsPointers(t *testing.T) {
 	UseTokenType = QuestionMarkTokenType
-	ins := Insert{tbl, fiveRecsPointers}
+	ins := Insert{Table: tbl, Data: fiveRecsPointers}
 	expected := `(?,?,?,?,?,?,?),(?,?,?,?,?,?,?),(?,?,?,?,?,?,?),(?,?,?,?,?,?,?),(?,?,?,?,?,?,?)`
 	params := ins.Params()
 	if expected != params {
@@ -103,7 +103,7 @@ func TestParamsManyRecsPointers(t *testing.T) {
 
 func TestSQLOneRecValue(t *testing.T) {
 	UseTokenType = OrdinalNumberTokenType
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ($1,$2,$3,$4,$5,$6,$7)`
 	insertSQL := ins.SQL()
 	if expected != insertSQL {
@@ -113,7 +113,7 @@ func TestSQLOneRecValue(t *testing.T) {
 
 func TestSQLOneRecPointer(t *testing.T) {
 	UseTokenType = OrdinalNumberTokenType
-	ins := Insert{tbl, recPointer}
+	ins := Insert{Table: tbl, Data: recPointer}
 	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ($1,$2,$3,$4,$5,$6,$7)`
 	insertSQL := ins.SQL()
 	if expected != insertSQL {
@@ -125,8 +125,8 @@ func TestSQLOneRecPointer(t *testing.T) {
 
 func TestSQLManyRecsValues(t *testing.T) {
 	UseTokenType = OrdinalNumberTokenType
-	ins := Insert{tbl, fiveRecsValues}
-	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7)`
+	ins := Insert{Table: tbl, Data: fiveRecsValues}
+	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14),($15,$16,$17,$18,$19,$20,$21),($22,$23,$24,$25,$26,$27,$28),($29,$30,$31,$32,$33,$34,$35)`
 	insertSQL := ins.SQL()
 	if expected != insertSQL {
 		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
@@ -135,8 +135,18 @@ func TestSQLManyRecsValues(t *testing.T) {
 
 func TestSQLManyRecsPointers(t *testing.T) {
 	UseTokenType = OrdinalNumberTokenType
-	ins := Insert{tbl, fiveRecsPointers}
-	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7),($1,$2,$3,$4,$5,$6,$7)`
+	ins := Insert{Table: tbl, Data: fiveRecsPointers}
+	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14),($15,$16,$17,$18,$19,$20,$21),($22,$23,$24,$25,$26,$27,$28),($29,$30,$31,$32,$33,$34,$35)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestSQLManyRowsOracle(t *testing.T) {
+	UseTokenType = OracleDialect.TokenType()
+	ins := NewInsert(tbl, Rows{Columns: []string{`id`}, Values: [][]interface{}{{1}, {2}}}, WithDialect(OracleDialect))
+	expected := `INSERT ALL INTO candy (id) VALUES (:id_1) INTO candy (id) VALUES (:id_2) SELECT 1 FROM DUAL`
 	insertSQL := ins.SQL()
 	if expected != insertSQL {
 		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
@@ -148,7 +158,7 @@ func TestSQLManyRecsPointers(t *testing.T) {
 // - Single-row Insert.Args
 
 func TestArgsOneRecValue(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := []interface{}{
 		`c0600afd-78a7-4a1a-87c5-1bc48cafd14e`,
 		`Gougat`,
@@ -165,7 +175,7 @@ func TestArgsOneRecValue(t *testing.T) {
 }
 
 func TestArgsOneRecPointer(t *testing.T) {
-	ins := Insert{tbl, recPointer}
+	ins := Insert{Table: tbl, Data: recPointer}
 	expected := []interface{}{
 		`c0600afd-78a7-4a1a-87c5-1bc48cafd14e`,
 		`Gougat`,
@@ -184,7 +194,7 @@ func TestArgsOneRecPointer(t *testing.T) {
 // - Multi-row Insert.Args
 
 func TestArgsManyRecsValues(t *testing.T) {
-	ins := Insert{tbl, fiveRecsValues}
+	ins := Insert{Table: tbl, Data: fiveRecsValues}
 	expected := []interface{}{
 		`a`, `a`, `a`, `a`, `a`, 1.1, time.Time{},
 		`b`, `b`, `b`, `b`, `b`, 2.1, time.Time{},
@@ -199,7 +209,7 @@ func TestArgsManyRecsValues(t *testing.T) {
 }
 
 func TestArgsManyRecsPointers(t *testing.T) {
-	ins := Insert{tbl, fiveRecsPointers}
+	ins := Insert{Table: tbl, Data: fiveRecsPointers}
 	expected := []interface{}{
 		`a`, `a`, `a`, `a`, `a`, 1.1, time.Time{},
 		`b`, `b`, `b`, `b`, `b`, 2.1, time.Time{},
@@ -213,6 +223,52 @@ func TestArgsManyRecsPointers(t *testing.T) {
 	}
 }
 
+/* Insert.DebugSQL */
+
+func TestDebugSQLOneRecValue(t *testing.T) {
+	ins := Insert{Table: tbl, Data: recValue}
+	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ` +
+		`(N'c0600afd-78a7-4a1a-87c5-1bc48cafd14e',N'Gougat',N'Package',N'tastes like gopher feed',N'Gouggle',` +
+		`1.1618,'0001-01-01T00:00:00+00:00')`
+	debugSQL := ins.DebugSQL(SQLServerDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+func TestDebugSQLManyRecsPointers(t *testing.T) {
+	ins := Insert{Table: tbl, Data: fiveRecsPointers[:2]}
+	expected := `INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES ` +
+		`('a','a','a','a','a',1.1,'0001-01-01 00:00:00'),('b','b','b','b','b',2.1,'0001-01-01 00:00:00')`
+	debugSQL := ins.DebugSQL(MySQLDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+/* Insert.Batches */
+
+func TestBatchesManyRecsValues(t *testing.T) {
+	ins := Insert{Table: tbl, Data: fiveRecsValues}
+	batches := ins.Batches(2)
+	if len(batches) != 3 {
+		t.Fatalf(`expected 3 batches, got %d`, len(batches))
+	}
+	for i, expected := range [][]candyInsert{fiveRecsValues[0:2], fiveRecsValues[2:4], fiveRecsValues[4:5]} {
+		if batches[i].Table != tbl || !reflect.DeepEqual(expected, batches[i].Data) {
+			t.Fatalf(`expected "%v", got "%v"`, expected, batches[i].Data)
+		}
+	}
+}
+
+func TestBatchesOneRecPointer(t *testing.T) {
+	ins := Insert{Table: tbl, Data: recPointer}
+	batches := ins.Batches(2)
+	if len(batches) != 1 || batches[0].Data != recPointer {
+		t.Fatalf(`expected the insert itself, got "%v"`, batches)
+	}
+}
+
 /* INSERT */
 
 // - Single-row Insert.Insert, Insert.InsertContext
@@ -221,7 +277,7 @@ func TestArgsManyRecsPointers(t *testing.T) {
 func TestInsertOneRecValue(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, recValue}
+		ins := Insert{Table: tbl, Data: recValue}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -240,7 +296,7 @@ func TestInsertOneRecValue(t *testing.T) {
 func TestInsertOneRecPointer(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, recPointer}
+		ins := Insert{Table: tbl, Data: recPointer}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -259,7 +315,7 @@ func TestInsertOneRecPointer(t *testing.T) {
 func TestInsertContextOneRecValue(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, recValue}
+		ins := Insert{Table: tbl, Data: recValue}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -278,7 +334,7 @@ func TestInsertContextOneRecValue(t *testing.T) {
 func TestInsertContextOneRecPointer(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, recPointer}
+		ins := Insert{Table: tbl, Data: recPointer}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -299,7 +355,7 @@ func TestInsertContextOneRecPointer(t *testing.T) {
 func TestInsertManyRecsValues(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, fiveRecsValues}
+		ins := Insert{Table: tbl, Data: fiveRecsValues}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -318,7 +374,7 @@ func TestInsertManyRecsValues(t *testing.T) {
 func TestInsertManyRecsPointers(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, fiveRecsPointers}
+		ins := Insert{Table: tbl, Data: fiveRecsPointers}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -337,7 +393,7 @@ func TestInsertManyRecsPointers(t *testing.T) {
 func TestInsertContextManyRecsValues(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, fiveRecsValues}
+		ins := Insert{Table: tbl, Data: fiveRecsValues}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
@@ -356,7 +412,7 @@ func TestInsertContextManyRecsValues(t *testing.T) {
 func TestInsertContextManyRecsPointers(t *testing.T) {
 	for tt := range valuesTokenTypes {
 		UseTokenType = TokenType(tt)
-		ins := Insert{tbl, fiveRecsPointers}
+		ins := Insert{Table: tbl, Data: fiveRecsPointers}
 		s := regexp.QuoteMeta(ins.SQL())
 		db, mock, err := sqlmock.New()
 		if err != nil {
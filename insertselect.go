@@ -0,0 +1,101 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// InsertSelect models an INSERT ... SELECT statement, e.g., to copy rows from one table to another:
+//
+//	INSERT INTO archive (id,candy_name) SELECT id,candy_name FROM candy WHERE ts < ?
+//
+// Table is the target table. Data is anything accepted as Insert.Data, of which only the columns are used, e.g.,
+// a zero struct, or Rows with Columns and no Values. Select is the source query, whose ? bind params (outside
+// single-quoted strings and double-quoted identifiers) are converted to the token type of the Insert, e.g., $1,
+// @p1, or :p1, and SelectArgs are their args. Options are set by With, as for an Insert; of them, Only, Exclude,
+// WithDialect, WithTokenType, and WithObserver apply, and the others, which concern row values, are ignored.
+type InsertSelect struct {
+	Table      string
+	Data       interface{}
+	Select     string
+	SelectArgs []interface{}
+	opts       *insertOptions
+}
+
+// With applies the options to the InsertSelect and returns it.
+func (is *InsertSelect) With(opts ...Option) *InsertSelect {
+	is.opts = is.insert().With(opts...).opts
+	return is
+}
+
+// insert returns the Insert of the columns of the InsertSelect, with its options.
+func (is *InsertSelect) insert() *Insert {
+	return &Insert{Table: is.Table, Data: is.Data, opts: is.opts}
+}
+
+// Columns returns the comma-separated list of column names of Data, enclosed in parentheses, as Insert.Columns.
+func (is *InsertSelect) Columns() string {
+	return is.insert().Columns()
+}
+
+// SQL returns the full parameterized INSERT ... SELECT statement.
+func (is *InsertSelect) SQL() string {
+	query := is.Select
+	if tokenType := is.insert().tokenType(); tokenType != QuestionMarkTokenType && tokenType != ColumnNameTokenType {
+		var b strings.Builder
+		start := 0
+		for i, index := range placeholders(query) {
+			_, _ = fmt.Fprintf(&b, `%s%s`, query[start:index], paramToken(selectParamName(i), tokenType, i+1))
+			start = index + 1
+		}
+		b.WriteString(query[start:])
+		query = b.String()
+	}
+	return fmt.Sprintf(`INSERT INTO %s %s %s`, is.Table, is.Columns(), query)
+}
+
+// Args returns the bind args of the SELECT: SelectArgs, as sql.NamedArg values named p1, p2, etc., as their tokens,
+// if the token type is a named token type.
+func (is *InsertSelect) Args() []interface{} {
+	if !is.insert().tokenType().named() {
+		return is.SelectArgs
+	}
+	args := make([]interface{}, len(is.SelectArgs))
+	for i, arg := range is.SelectArgs {
+		if _, ok := arg.(sql.NamedArg); !ok {
+			arg = sql.Named(selectParamName(i), arg)
+		}
+		args[i] = arg
+	}
+	return args
+}
+
+// selectParamName returns the name of the bind param of the SELECT at index i for the named token types.
+func selectParamName(i int) string {
+	return fmt.Sprintf(`p%d`, i+1)
+}
+
+// Insert prepares and executes the INSERT ... SELECT statement on a *sql.DB, *sql.Tx, StmtCache, or other
+// Inserter-compatible interface to Prepare and Exec. It is InsertContext with context.Background().
+func (is *InsertSelect) Insert(with InsertWith) (*sql.Stmt, error) {
+	return is.InsertContext(context.Background(), with)
+}
+
+// InsertContext prepares and executes the INSERT ... SELECT statement on a *sql.DB, *sql.Tx, *sql.Conn, StmtCache,
+// or other Inserter-compatible interface to PrepareContext and ExecContext. If with is a StmtCache, nil is returned
+// in place of the statement, as by Insert.InsertContext.
+func (is *InsertSelect) InsertContext(ctx context.Context, with InsertWith) (*sql.Stmt, error) {
+	query, args := is.SQL(), is.Args()
+	ctx, finish := observe(ctx, is.insert().options().observer, query, args, 0)
+	stmt, done, err := prepare(ctx, with, query)
+	if err != nil {
+		finish(nil, err)
+		return nil, err
+	}
+	result, err := stmt.ExecContext(ctx, args...)
+	done(err)
+	finish(result, err)
+	return callerStmt(with, stmt), err
+}
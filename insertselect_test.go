@@ -0,0 +1,137 @@
+package sqlinsert
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+/* InsertSelect */
+
+func TestInsertSelectSQL(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	is := InsertSelect{
+		Table:      `archive`,
+		Data:       candyInsert{},
+		Select:     `SELECT * FROM candy WHERE manufacturer = ? AND candy_name <> '?'`,
+		SelectArgs: []interface{}{`Gouggle`},
+	}
+	expected := `INSERT INTO archive (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) ` +
+		`SELECT * FROM candy WHERE manufacturer = ? AND candy_name <> '?'`
+	insertSQL := is.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{`Gouggle`}
+	if args := is.Args(); !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestInsertSelectSQLOrdinal(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	is := InsertSelect{
+		Table:  `archive`,
+		Data:   Rows{Columns: []string{`id`, `candy_name`}},
+		Select: `SELECT id,candy_name FROM candy WHERE ts < ? AND manufacturer = ?`,
+	}
+	expected := `INSERT INTO archive (id,candy_name) SELECT id,candy_name FROM candy WHERE ts < $1 AND manufacturer = $2`
+	insertSQL := is.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestInsertSelectSQLNamed(t *testing.T) {
+	defer func() { UseTokenType = QuestionMarkTokenType }()
+	for _, tc := range []struct {
+		tokenType TokenType
+		expected  string
+	}{
+		{ColonTokenType, `INSERT INTO archive (id,"?") SELECT id,"?" FROM candy WHERE ts < :p1 AND manufacturer = :p2`},
+		{AtColumnNameTokenType, `INSERT INTO archive (id,"?") SELECT id,"?" FROM candy WHERE ts < @p1 AND manufacturer = @p2`},
+	} {
+		UseTokenType = tc.tokenType
+		is := InsertSelect{
+			Table:      `archive`,
+			Data:       Rows{Columns: []string{`id`, `"?"`}},
+			Select:     `SELECT id,"?" FROM candy WHERE ts < ? AND manufacturer = ?`,
+			SelectArgs: []interface{}{1, `Gouggle`},
+		}
+		insertSQL := is.SQL()
+		if tc.expected != insertSQL {
+			t.Fatalf(`expected "%s", got "%s"`, tc.expected, insertSQL)
+		}
+		expectedArgs := []interface{}{sql.Named(`p1`, 1), sql.Named(`p2`, `Gouggle`)}
+		if args := is.Args(); !reflect.DeepEqual(expectedArgs, args) {
+			t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+		}
+	}
+}
+
+func TestInsertSelectInsert(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	is := InsertSelect{
+		Table:      `archive`,
+		Data:       &candyInsert{},
+		Select:     `SELECT * FROM candy WHERE manufacturer = ?`,
+		SelectArgs: []interface{}{`Gouggle`},
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(is.SQL())).ExpectExec().WithArgs(`Gouggle`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	if _, err = is.Insert(db); err != nil {
+		t.Fatal(err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInsertSelectSQLAtPOrdinal(t *testing.T) {
+	defer func(tokenType TokenType) { UseTokenType = tokenType }(UseTokenType)
+	UseTokenType = AtPOrdinalTokenType
+	is := InsertSelect{
+		Table:  `archive`,
+		Data:   Rows{Columns: []string{`id`}},
+		Select: `SELECT id FROM candy WHERE ts < ? AND candy_name <> ?`,
+	}
+	expected := `INSERT INTO archive (id) SELECT id FROM candy WHERE ts < @p1 AND candy_name <> @p2`
+	if insertSQL := is.SQL(); expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestInsertSelectWith(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	recorder := &Recorder{}
+	is := (&InsertSelect{
+		Table:      `archive`,
+		Data:       Rows{Columns: []string{`id`, `candy_name`, `ts`}},
+		Select:     `SELECT id,candy_name FROM candy WHERE ts < ?`,
+		SelectArgs: []interface{}{1},
+	}).With(Exclude(`ts`), WithDialect(PostgresDialect), WithObserver(recorder))
+	expected := `INSERT INTO archive (id,candy_name) SELECT id,candy_name FROM candy WHERE ts < $1`
+	insertSQL := is.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectPrepare(regexp.QuoteMeta(insertSQL)).ExpectExec().WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	if _, err = is.Insert(db); err != nil {
+		t.Fatal(err)
+	}
+	events := recorder.Events()
+	if len(events) != 1 || events[0].Query != insertSQL || events[0].RowsAffected != 3 {
+		t.Fatalf(`expected 1 event of 3 rows, got %+v`, events)
+	}
+}
@@ -0,0 +1,216 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// IsolateErrors isolates the rows that make a multi-row insert fail, e.g., one row of 5,000 that violates a
+// constraint, so that the other rows are still inserted. When a statement fails, its rows are split in two halves
+// that are inserted in turn, and so on down to the single rows that fail, at the cost of about two statements per
+// failing row per halving. If both halves fail to build or prepare with the same error, e.g., for a missing table,
+// no row is to blame, and the insert fails with it. In a *sql.Tx, each statement runs inside a savepoint, which is
+// rolled back on failure, so that the transaction stays usable (a failed statement aborts a Postgres transaction).
+//
+// Insert.ExecContext and Insert.InsertContext return the RowErrors of the failing rows, with the rows inserted;
+// ExecContext also reports them in InsertResult.Failed.
+func IsolateErrors() Option {
+	return func(ins *Insert) {
+		ins.configure().isolateErrors = true
+	}
+}
+
+// RowError reports a row that failed to insert under IsolateErrors or DeadLetters. Row is the index of the row in
+// Insert.Data, Data is the row, e.g., the struct (pointer) or the values of Rows, SQL is the statement that failed,
+// if it was built, and Err is its error, that of the row alone if isolated.
+type RowError struct {
+	Row  int
+	Data interface{}
+	SQL  string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *RowError) Error() string {
+	return fmt.Sprintf(`sqlinsert: row %d: %s`, e.Row, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowErrors is the error of an insert under IsolateErrors in which rows failed, one RowError per row in order.
+type RowErrors []*RowError
+
+// Error returns the error message, which names the first failing row.
+func (errs RowErrors) Error() string {
+	if len(errs) == 0 {
+		return `sqlinsert: no failing rows`
+	}
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	return fmt.Sprintf(`%s (and %d more failing rows)`, errs[0], len(errs)-1)
+}
+
+// Unwrap returns the RowErrors as a []error, for errors.Is and errors.As.
+func (errs RowErrors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// savepoints are the statements to set, roll back to, and release a savepoint, by dialect; other dialects use
+// defaultSavepoint. SQL Server and Oracle release savepoints at the end of the transaction only.
+var savepoints = map[Dialect][3]string{
+	SQLServerDialect: {`SAVE TRANSACTION %s`, `ROLLBACK TRANSACTION %s`, ``},
+	OracleDialect:    {`SAVEPOINT %s`, `ROLLBACK TO SAVEPOINT %s`, ``},
+}
+
+var defaultSavepoint = [3]string{`SAVEPOINT %s`, `ROLLBACK TO SAVEPOINT %s`, `RELEASE SAVEPOINT %s`}
+
+// savepointName names the savepoint of IsolateErrors.
+const savepointName = `sqlinsert_isolate`
+
+// execIsolated is exec for IsolateErrors: if the statement fails, it isolates the failing rows, adding the rows
+// affected to total and the RowError of each failing row to total.Failed. It returns the statement and result of the
+// last statement that succeeded, if any, and only errors that no row is to blame for: a canceled context, a failed
+// savepoint, or a statement that fails to build or prepare with the same error for both halves of the rows.
+func (ins *Insert) execIsolated(ctx context.Context, with InsertWith, total *InsertResult) (*sql.Stmt, sql.Result,
+	error) {
+	stmt, result, err := ins.execSavepoint(ctx, with)
+	if err == nil {
+		total.addStatement(ins, result)
+		return stmt, result, nil
+	}
+	return ins.isolate(ctx, with, total, err)
+}
+
+// isolate inserts each half of the rows of the insert, whose statement failed with err, in turn, and then isolates
+// the failing rows of each half that fails, and so on down to the single rows that fail.
+func (ins *Insert) isolate(ctx context.Context, with InsertWith, total *InsertResult, err error) (*sql.Stmt,
+	sql.Result, error) {
+	if _, ok := err.(*savepointError); ok || ctx.Err() != nil {
+		return nil, nil, err
+	}
+	rows := ins.rows()
+	if len(rows) == 1 {
+		total.Failed = append(total.Failed, ins.rowErrors(err)...)
+		return nil, nil, nil
+	}
+	var (
+		first  = make([]int, len(rows)/2)
+		second = make([]int, len(rows)-len(first))
+		stmt   *sql.Stmt
+		result sql.Result
+		halves [2]Insert
+		errs   [2]error
+		built  [2]bool
+	)
+	for i := range first {
+		first[i] = i
+	}
+	for i := range second {
+		second[i] = len(first) + i
+	}
+	for k, half := range [][]int{first, second} {
+		halves[k] = ins.subset(half)
+		halfStmt, halfResult, err := halves[k].execSavepoint(ctx, with)
+		if err == nil {
+			total.addStatement(&halves[k], halfResult)
+			stmt, result = halfStmt, halfResult
+			continue
+		}
+		if _, ok := err.(*savepointError); ok || ctx.Err() != nil {
+			return halfStmt, nil, err
+		}
+		errs[k], built[k] = err, halfStmt != nil
+	}
+	// A statement that fails before it executes, e.g., for a missing table, fails for every row alike
+	if errs[0] != nil && errs[1] != nil && !built[0] && !built[1] && errs[0].Error() == errs[1].Error() {
+		return nil, nil, errs[0]
+	}
+	for k := range halves {
+		if errs[k] == nil {
+			continue
+		}
+		halfStmt, halfResult, err := halves[k].isolate(ctx, with, total, errs[k])
+		if err != nil {
+			return halfStmt, nil, err
+		}
+		if halfResult != nil {
+			stmt, result = halfStmt, halfResult
+		}
+	}
+	return stmt, result, nil
+}
+
+// isolates reports whether the insert isolates failing rows, by IsolateErrors or the DeadLetterPolicy.
+func (ins *Insert) isolates() bool {
+	return ins.options().isolateErrors || ins.options().deadLetters != nil && ins.options().deadLetters.policy.Isolate
+}
+
+// rowErrors returns a RowError with err for each row of the insert, whose statement failed with err.
+func (ins *Insert) rowErrors(err error) []*RowError {
+	query, _, buildErr := ins.Build()
+	if buildErr != nil {
+		query = ``
+	}
+	rows := ins.rows()
+	rowErrs := make([]*RowError, len(rows))
+	for i, row := range rows {
+		rowErrs[i] = &RowError{Row: ins.rowNumber(i), Data: row.Interface(), SQL: query, Err: err}
+	}
+	return rowErrs
+}
+
+// savepointError is the error of a savepoint statement of IsolateErrors.
+type savepointError struct {
+	err error
+}
+
+// Error returns the error message.
+func (e *savepointError) Error() string {
+	return fmt.Sprintf(`sqlinsert: savepoint: %s`, e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *savepointError) Unwrap() error {
+	return e.err
+}
+
+// execSavepoint is exec inside a savepoint if with is a *sql.Tx: the savepoint is rolled back if the statement
+// fails, and released if it succeeds.
+func (ins *Insert) execSavepoint(ctx context.Context, with InsertWith) (*sql.Stmt, sql.Result, error) {
+	if _, ok := with.(*sql.Tx); !ok {
+		return ins.exec(ctx, with)
+	}
+	statements, ok := savepoints[ins.options().dialect]
+	if !ok {
+		statements = defaultSavepoint
+	}
+	savepoint := func(statement string) error {
+		if statement == `` {
+			return nil
+		}
+		if _, err := with.ExecContext(ctx, fmt.Sprintf(statement, savepointName)); err != nil {
+			return &savepointError{err}
+		}
+		return nil
+	}
+	if err := savepoint(statements[0]); err != nil {
+		return nil, nil, err
+	}
+	stmt, result, err := ins.exec(ctx, with)
+	if err != nil {
+		if rollbackErr := savepoint(statements[1]); rollbackErr != nil {
+			return stmt, nil, rollbackErr
+		}
+		return stmt, nil, err
+	}
+	return stmt, result, savepoint(statements[2])
+}
@@ -0,0 +1,150 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"testing"
+)
+
+/* IsolateErrors */
+
+var errConstraint = errors.New(`constraint violated`)
+
+func TestExecContextIsolateErrors(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rows := Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}, {`b`}, {`c`}, {`bad`}}}
+	ins := NewInsert(tbl, rows, IsolateErrors())
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	for _, step := range []struct {
+		sql  string
+		args []driver.Value
+		err  error
+	}{
+		{`INSERT INTO candy (id) VALUES (?),(?),(?),(?)`, []driver.Value{`a`, `b`, `c`, `bad`}, errConstraint},
+		{`INSERT INTO candy (id) VALUES (?),(?)`, []driver.Value{`a`, `b`}, nil},
+		{`INSERT INTO candy (id) VALUES (?),(?)`, []driver.Value{`c`, `bad`}, errConstraint},
+		{`INSERT INTO candy (id) VALUES (?)`, []driver.Value{`c`}, nil},
+		{`INSERT INTO candy (id) VALUES (?)`, []driver.Value{`bad`}, errConstraint},
+	} {
+		s := regexp.QuoteMeta(step.sql)
+		mock.ExpectPrepare(s)
+		exec := mock.ExpectExec(s).WithArgs(step.args...)
+		if step.err != nil {
+			exec.WillReturnError(step.err)
+		} else {
+			exec.WillReturnResult(sqlmock.NewResult(0, int64(len(step.args))))
+		}
+	}
+	result, err := ins.ExecContext(context.Background(), db)
+	var rowErrs RowErrors
+	if !errors.As(err, &rowErrs) || !errors.Is(err, errConstraint) {
+		t.Fatalf(`expected RowErrors, got "%v"`, err)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Row != 3 || result.Failed[0].Data.([]interface{})[0] != `bad` {
+		t.Fatalf(`expected row 3 to fail, got %+v`, result.Failed)
+	}
+	if result.Rows != 4 || result.Inserted != 3 || result.Skipped != 0 {
+		t.Fatalf(`expected 3 of 4 rows inserted, got %+v`, result)
+	}
+	expected := `sqlinsert: row 3: constraint violated`
+	if expected != err.Error() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestExecContextIsolateErrorsSavepoint(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	recs := []candyInsert{recValue, recValue}
+	ins := NewInsert(tbl, recs, Only(`id`), IsolateErrors(), WithDialect(PostgresDialect))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf(`failed at Begin: %s`, err)
+	}
+	savepoint := regexp.QuoteMeta(`SAVEPOINT sqlinsert_isolate`)
+	rollback := regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT sqlinsert_isolate`)
+	release := regexp.QuoteMeta(`RELEASE SAVEPOINT sqlinsert_isolate`)
+	mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+	s := regexp.QuoteMeta(`INSERT INTO candy (id) VALUES ($1),($2)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnError(errConstraint)
+	mock.ExpectExec(rollback).WillReturnResult(sqlmock.NewResult(0, 0))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id) VALUES ($1)`)
+	for _, err := range []error{errConstraint, nil} {
+		mock.ExpectExec(savepoint).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectPrepare(s)
+		if err != nil {
+			mock.ExpectExec(s).WillReturnError(err)
+			mock.ExpectExec(rollback).WillReturnResult(sqlmock.NewResult(0, 0))
+		} else {
+			mock.ExpectExec(s).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(release).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+	}
+	result, err := ins.ExecContext(context.Background(), tx)
+	if !errors.Is(err, errConstraint) {
+		t.Fatalf(`expected constraint error, got "%v"`, err)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Row != 0 || result.Failed[0].Data != &recs[0] {
+		t.Fatalf(`expected row 0 to fail, got %+v`, result.Failed)
+	}
+	if result.Inserted != 1 {
+		t.Fatalf(`expected 1 row inserted, got %d`, result.Inserted)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestExecContextIsolateErrorsPrepare(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rows := Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}, {`b`}, {`c`}, {`d`}}}
+	ins := NewInsert(tbl, rows, IsolateErrors())
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	errMissing := errors.New(`relation "candy" does not exist`)
+	mock.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?),(?),(?)`)).WillReturnError(errMissing)
+	s := regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?)`)
+	mock.ExpectPrepare(s).WillReturnError(errMissing)
+	mock.ExpectPrepare(s).WillReturnError(errMissing)
+	result, err := ins.ExecContext(context.Background(), db)
+	if err != errMissing {
+		t.Fatalf(`expected "%v", got "%v"`, errMissing, err)
+	}
+	if result != nil {
+		t.Fatalf(`expected no result, got %+v`, result)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestRowErrorsError(t *testing.T) {
+	err := RowErrors{{Row: 2, Err: errConstraint}, {Row: 7, Err: errConstraint}}
+	expected := `sqlinsert: row 2: constraint violated (and 1 more failing rows)`
+	if expected != err.Error() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, err)
+	}
+}
+
+func TestRowErrorsErrorEmpty(t *testing.T) {
+	expected := `sqlinsert: no failing rows`
+	if err := (RowErrors{}); expected != err.Error() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, err)
+	}
+}
@@ -0,0 +1,294 @@
+package sqlinsert
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Coercion specifies the conversion of a decoded JSON value to the bind arg of its column.
+type Coercion int
+
+const (
+
+	// NoCoercion binds JSON strings and booleans as is, numbers as int64 (if integral) or float64, and objects and
+	// arrays as JSON text.
+	NoCoercion Coercion = 0
+
+	// TimeCoercion binds RFC 3339 strings and numbers of seconds since the Unix epoch as time.Time.
+	TimeCoercion Coercion = 1
+
+	// NumberCoercion binds numbers and numeric strings as int64 (if integral) or float64.
+	NumberCoercion Coercion = 2
+
+	// JSONCoercion binds any JSON value but null as JSON text, e.g., for JSON columns.
+	JSONCoercion Coercion = 3
+)
+
+// JSONDecoder decodes a stream of JSON objects, either newline-delimited (NDJSON) or in a JSON array, into rows
+// of Rows. JSON null is NULL.
+//
+// Columns are the columns of the rows, in order. If Columns is empty, they become the columns of the keys of the
+// objects, in document order, and the key of a later object that is not among them adds a column, of which the rows
+// decoded before it have no value: DecodeRows gives them the value of a missing key, so that the columns of Rows are
+// those of every key of its objects. Otherwise, keys that are not columns are ignored. Keys maps JSON keys to column
+// names; a key that is not mapped is its own column name. Coerce specifies the Coercion of the values of each column;
+// the default is NoCoercion. A column whose key is missing from an object is NULL, or Default if MissingDefault.
+type JSONDecoder struct {
+	Columns        []string
+	Keys           map[string]string
+	Coerce         map[string]Coercion
+	MissingDefault bool
+
+	r               *bufio.Reader
+	dec             *json.Decoder
+	inArray         bool
+	row             int
+	columnsFromKeys bool
+}
+
+// NewJSONDecoder returns a JSONDecoder that reads from r.
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return &JSONDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode returns the values of the next row, in column order, or io.EOF at the end of the stream.
+func (d *JSONDecoder) Decode() ([]interface{}, error) {
+	if d.dec == nil {
+		if err := d.start(); err != nil {
+			return nil, err
+		}
+	}
+	if !d.dec.More() {
+		return nil, io.EOF
+	}
+	d.row++
+	keys, rawValues, err := decodeObject(d.dec)
+	if err != nil {
+		return nil, fmt.Errorf(`sqlinsert: JSON row %d: %w`, d.row, err)
+	}
+	if len(d.Columns) == 0 {
+		d.columnsFromKeys = true
+	}
+	values := make([]interface{}, len(d.Columns))
+	for i := range values {
+		values[i] = d.missing()
+	}
+	for _, key := range keys {
+		column := d.column(key)
+		i := indexOf(d.Columns, column)
+		if i < 0 {
+			if !d.columnsFromKeys {
+				continue
+			}
+			d.Columns = append(d.Columns, column)
+			values = append(values, nil)
+			i = len(values) - 1
+		}
+		if values[i], err = coerce(rawValues[key], d.Coerce[column]); err != nil {
+			return nil, fmt.Errorf(`sqlinsert: JSON row %d: column %s: %w`, d.row, column, err)
+		}
+	}
+	return values, nil
+}
+
+// DecodeRows decodes up to n rows, or all remaining rows if n is less than 1. At the end of the stream, it returns
+// Rows with no Values.
+func (d *JSONDecoder) DecodeRows(n int) (*Rows, error) {
+	var rowValues [][]interface{}
+	for n < 1 || len(rowValues) < n {
+		values, err := d.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rowValues = append(rowValues, values)
+	}
+	for i, values := range rowValues {
+		for len(values) < len(d.Columns) {
+			values = append(values, d.missing())
+		}
+		rowValues[i] = values
+	}
+	return &Rows{Columns: append([]string(nil), d.Columns...), Values: rowValues}, nil
+}
+
+// missing returns the value of a column whose key is missing from an object: NULL, or Default if MissingDefault.
+func (d *JSONDecoder) missing() interface{} {
+	if d.MissingDefault {
+		return Default
+	}
+	return nil
+}
+
+// InsertJSON decodes the rows of dec and inserts them into table with multi-row INSERT statements of up to
+// batchSize rows each, as they are decoded, via Insert.ExecContext with the options, e.g., DeadLetters to keep
+// going past rows that fail. It returns the number of rows inserted, less those that failed.
+func InsertJSON(ctx context.Context, with InsertWith, table string, dec *JSONDecoder, batchSize int,
+	opts ...Option) (int, error) {
+	if batchSize < 1 {
+		return 0, errors.New(`sqlinsert: batch size must be at least 1`)
+	}
+	inserted := 0
+	for {
+		rows, err := dec.DecodeRows(batchSize)
+		if err != nil {
+			return inserted, err
+		}
+		if len(rows.Values) == 0 {
+			return inserted, nil
+		}
+		result, err := NewInsert(table, rows, opts...).ExecContext(ctx, with)
+		if result != nil {
+			inserted += result.Rows - len(result.Failed)
+		}
+		if err != nil {
+			return inserted, err
+		}
+	}
+}
+
+// start begins decoding the stream, entering the JSON array if the stream is one.
+func (d *JSONDecoder) start() error {
+	d.dec = json.NewDecoder(d.r)
+	for {
+		b, err := d.r.Peek(1)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = d.r.ReadByte()
+			continue
+		case '[':
+			d.inArray = true
+			_, err = d.dec.Token()
+			return err
+		}
+		return nil
+	}
+}
+
+// column returns the column name of a JSON key.
+func (d *JSONDecoder) column(key string) string {
+	if column, ok := d.Keys[key]; ok {
+		return column
+	}
+	return key
+}
+
+// decodeObject decodes the next JSON object of dec, returning its keys in document order and its values as raw
+// JSON.
+func decodeObject(dec *json.Decoder) ([]string, map[string]json.RawMessage, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := t.(json.Delim); !ok || delim != '{' {
+		return nil, nil, fmt.Errorf(`expected JSON object, got %v`, t)
+	}
+	var (
+		keys   []string
+		values = make(map[string]json.RawMessage)
+	)
+	for dec.More() {
+		t, err = dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := t.(string)
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = raw
+	}
+	_, err = dec.Token() // }
+	return keys, values, err
+}
+
+// coerce converts a raw JSON value to a bind arg.
+func coerce(raw json.RawMessage, coercion Coercion) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	switch coercion {
+	case TimeCoercion:
+		switch v := v.(type) {
+		case string:
+			return time.Parse(time.RFC3339Nano, v)
+		case json.Number:
+			seconds, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			whole, frac := math.Modf(seconds)
+			return time.Unix(int64(whole), int64(frac*1e9)).UTC(), nil
+		}
+		return nil, fmt.Errorf(`cannot coerce %s to time`, raw)
+	case NumberCoercion:
+		switch v := v.(type) {
+		case string:
+			return number(json.Number(v))
+		case json.Number:
+			return number(v)
+		}
+		return nil, fmt.Errorf(`cannot coerce %s to number`, raw)
+	case JSONCoercion:
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return nil, err
+		}
+		return b.String(), nil
+	}
+	switch v := v.(type) {
+	case json.Number:
+		return number(v)
+	case map[string]interface{}, []interface{}:
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return nil, err
+		}
+		return b.String(), nil
+	}
+	return v, nil
+}
+
+// number returns n as int64 if it is integral and in range, otherwise as float64.
+func number(n json.Number) (interface{}, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i, nil
+	}
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// indexOf returns the index of s in ss, or -1 if it is not there.
+func indexOf(ss []string, s string) int {
+	for i := range ss {
+		if ss[i] == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,125 @@
+package sqlinsert
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const candyNDJSON = `{"id": "a", "name": "Gougat", "weight": 1.1, "ts": "2022-03-04T05:06:07Z", "attrs": {"vegan": true}}
+{"id": "b", "name": "Gumdrop", "weight": "2", "ts": 1646370367, "attrs": null}
+{"name": "Toffee", "id": "c"}
+`
+
+/* JSONDecoder */
+
+func TestJSONDecoderNDJSON(t *testing.T) {
+	dec := NewJSONDecoder(strings.NewReader(candyNDJSON))
+	dec.Keys = map[string]string{`name`: `candy_name`, `weight`: `weight_grams`}
+	dec.Coerce = map[string]Coercion{`weight_grams`: NumberCoercion, `ts`: TimeCoercion, `attrs`: JSONCoercion}
+	rows, err := dec.DecodeRows(0)
+	if err != nil {
+		t.Fatalf(`failed at DecodeRows %s`, err)
+	}
+	expected := &Rows{
+		Columns: []string{`id`, `candy_name`, `weight_grams`, `ts`, `attrs`},
+		Values: [][]interface{}{
+			{`a`, `Gougat`, 1.1, time.Date(2022, time.March, 4, 5, 6, 7, 0, time.UTC), `{"vegan":true}`},
+			{`b`, `Gumdrop`, int64(2), time.Date(2022, time.March, 4, 5, 6, 7, 0, time.UTC), nil},
+			{`c`, `Toffee`, nil, nil, nil},
+		},
+	}
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, rows)
+	}
+}
+
+func TestJSONDecoderArrayMissingDefault(t *testing.T) {
+	dec := NewJSONDecoder(strings.NewReader(` [{"id": "a", "n": 1, "tags": ["x"]}, {"id": "b", "extra": true}]`))
+	dec.Columns = []string{`id`, `n`, `tags`}
+	dec.MissingDefault = true
+	rows, err := dec.DecodeRows(0)
+	if err != nil {
+		t.Fatalf(`failed at DecodeRows %s`, err)
+	}
+	expected := [][]interface{}{
+		{`a`, int64(1), `["x"]`},
+		{`b`, Default, Default},
+	}
+	if !reflect.DeepEqual(expected, rows.Values) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, rows.Values)
+	}
+	UseTokenType = OrdinalNumberTokenType
+	ins := Insert{Table: tbl, Data: rows}
+	expectedSQL := `INSERT INTO candy (id,n,tags) VALUES ($1,$2,$3),($4,DEFAULT,DEFAULT)`
+	insertSQL := ins.SQL()
+	if expectedSQL != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expectedSQL, insertSQL)
+	}
+	expectedArgs := []interface{}{`a`, int64(1), `["x"]`, `b`}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+	if _, err = dec.Decode(); err != io.EOF {
+		t.Fatalf(`expected EOF, got %v`, err)
+	}
+}
+
+func TestJSONDecoderLaterKey(t *testing.T) {
+	dec := NewJSONDecoder(strings.NewReader(`{"id": "a"}` + "\n" + `{"id": "b", "name": "Gumdrop"}`))
+	rows, err := dec.DecodeRows(0)
+	if err != nil {
+		t.Fatalf(`failed at DecodeRows %s`, err)
+	}
+	expected := &Rows{
+		Columns: []string{`id`, `name`},
+		Values:  [][]interface{}{{`a`, nil}, {`b`, `Gumdrop`}},
+	}
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, rows)
+	}
+}
+
+func TestJSONDecoderCoercionError(t *testing.T) {
+	dec := NewJSONDecoder(strings.NewReader(`{"id": "a", "ts": "yesterday"}`))
+	dec.Coerce = map[string]Coercion{`ts`: TimeCoercion}
+	_, err := dec.Decode()
+	if err == nil || !strings.Contains(err.Error(), `row 1: column ts`) {
+		t.Fatalf(`expected coercion error for row 1 column ts, got %v`, err)
+	}
+}
+
+/* InsertJSON */
+
+func TestInsertJSON(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id,name,weight,ts,attrs) VALUES (?,?,?,?,?),(?,?,?,?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnResult(sqlmock.NewResult(2, 2))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id,name,weight,ts,attrs) VALUES (?,?,DEFAULT,DEFAULT,DEFAULT)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`c`, `Toffee`).WillReturnResult(sqlmock.NewResult(3, 1))
+	dec := NewJSONDecoder(strings.NewReader(candyNDJSON))
+	dec.Columns = []string{`id`, `name`, `weight`, `ts`, `attrs`}
+	dec.MissingDefault = true
+	inserted, err := InsertJSON(context.Background(), db, tbl, dec, 2)
+	if err != nil {
+		t.Fatalf(`failed at InsertJSON %s`, err)
+	}
+	if inserted != 3 {
+		t.Fatalf(`expected 3 rows inserted, got %d`, inserted)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
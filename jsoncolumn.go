@@ -0,0 +1,28 @@
+package sqlinsert
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// UseJSONMarshal specifies the function that encodes the values of fields with the json tag option, e.g.,
+// `col:"attributes,json"`. Default is json.Marshal.
+var UseJSONMarshal = json.Marshal
+
+// encodeJSON returns v encoded by UseJSONMarshal as a string, or nil for a nil pointer, map, slice, or interface.
+func encodeJSON(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+	b, err := UseJSONMarshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
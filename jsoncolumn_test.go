@@ -0,0 +1,106 @@
+package sqlinsert
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type candyAttributes struct {
+	ID         string                 `col:"id"`
+	Attributes map[string]interface{} `col:"attributes,json=jsonb"`
+	Tags       []string               `col:"tags,json"`
+	Flavor     *struct{ Sweet bool }  `col:"flavor,json"`
+}
+
+var candyAttributesRecs = []candyAttributes{
+	{`a`, map[string]interface{}{`sugar`: 12, `vegan`: true}, []string{`chewy`}, nil},
+	{`b`, nil, []string{}, &struct{ Sweet bool }{true}},
+}
+
+/* JSON columns */
+
+func TestArgsJSON(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyAttributesRecs}
+	expected := []interface{}{
+		`a`, `{"sugar":12,"vegan":true}`, `["chewy"]`, nil,
+		`b`, nil, `[]`, `{"Sweet":true}`,
+	}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestArgsJSONMarshal(t *testing.T) {
+	defer func(marshal func(v interface{}) ([]byte, error)) { UseJSONMarshal = marshal }(UseJSONMarshal)
+	UseJSONMarshal = func(v interface{}) ([]byte, error) {
+		return []byte(`{}`), nil
+	}
+	ins := Insert{Table: tbl, Data: candyAttributesRecs[0]}
+	expected := []interface{}{`a`, `{}`, `{}`, nil}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestBuildJSONMarshalError(t *testing.T) {
+	ins := Insert{Table: tbl, Data: struct {
+		Ch chan int `col:"ch,json"`
+	}{make(chan int)}}
+	_, _, err := ins.Build()
+	var convertErr *ConvertError
+	if !errors.As(err, &convertErr) || convertErr.Column != `ch` || convertErr.Field != `Ch` {
+		t.Fatalf(`expected ConvertError for column ch, got %v`, err)
+	}
+}
+
+func TestParamsJSONCast(t *testing.T) {
+	defer func(tokenType TokenType) { UseTokenType = tokenType }(UseTokenType)
+	UseTokenType = OrdinalNumberTokenType
+	ins := NewInsert(tbl, candyAttributesRecs, WithDialect(PostgresDialect))
+	expected := `($1,$2::jsonb,$3,$4),($5,$6::jsonb,$7,$8)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+}
+
+func TestParamsJSONNoCast(t *testing.T) {
+	ins := NewInsert(tbl, candyAttributesRecs[0], WithDialect(MySQLDialect))
+	expected := `(?,?,?,?)`
+	params := ins.Params()
+	if expected != params {
+		t.Fatalf(`expected "%s", got "%s"`, expected, params)
+	}
+}
+
+func TestDebugSQLJSON(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyAttributesRecs}
+	expected := `INSERT INTO candy (id,attributes,tags,flavor) VALUES ` +
+		`('a','{"sugar":12,"vegan":true}'::jsonb,'["chewy"]',NULL),('b',NULL::jsonb,'[]','{"Sweet":true}')`
+	debugSQL := ins.DebugSQL(PostgresDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+func TestCreateTableSQLJSON(t *testing.T) {
+	expected := `CREATE TABLE candy (id TEXT NOT NULL,attributes JSONB,tags JSONB,flavor JSONB)`
+	createSQL, err := CreateTableSQL(PostgresDialect, tbl, candyAttributes{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected != createSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, createSQL)
+	}
+	expected = `CREATE TABLE candy (id VARCHAR(255) NOT NULL,attributes JSON,tags JSON,flavor JSON)`
+	createSQL, err = CreateTableSQL(MySQLDialect, tbl, candyAttributes{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected != createSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, createSQL)
+	}
+}
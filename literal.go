@@ -0,0 +1,125 @@
+package sqlinsert
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// literal returns v as a SQL literal of the dialect: NULL for nil, quoted and escaped strings, hex literals for
+// []byte, and so on. driver.Valuer values are converted first. Pointers are dereferenced.
+func literal(dialect Dialect, v interface{}) (string, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return `NULL`, nil
+		}
+		value, err := valuer.Value()
+		if err != nil {
+			return ``, err
+		}
+		v = value
+	}
+	if v == nil {
+		return `NULL`, nil
+	}
+	switch v := v.(type) {
+	case time.Time:
+		return timeLiteral(dialect, v), nil
+	case []byte:
+		if v == nil {
+			return `NULL`, nil
+		}
+		return bytesLiteral(dialect, v), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return `NULL`, nil
+		}
+		return literal(dialect, rv.Elem().Interface())
+	case reflect.String:
+		return stringLiteral(dialect, rv.String()), nil
+	case reflect.Bool:
+		return boolLiteral(dialect, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return stringLiteral(dialect, strconv.FormatFloat(f, 'g', -1, 64)), nil
+		}
+		return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			if rv.IsNil() {
+				return `NULL`, nil
+			}
+			return bytesLiteral(dialect, rv.Bytes()), nil
+		}
+	}
+	return ``, fmt.Errorf(`sqlinsert: no SQL literal for Go type %T`, v)
+}
+
+// stringLiteral quotes s, doubling single quotes. MySQL also escapes backslashes, and SQL Server strings are
+// Unicode (N'...').
+func stringLiteral(dialect Dialect, s string) string {
+	switch dialect {
+	case MySQLDialect, SingleStoreDialect:
+		s = strings.Replace(s, `\`, `\\`, -1)
+	case SQLServerDialect:
+		return `N'` + strings.Replace(s, `'`, `''`, -1) + `'`
+	}
+	return `'` + strings.Replace(s, `'`, `''`, -1) + `'`
+}
+
+// bytesLiteral returns b as a hexadecimal binary literal.
+func bytesLiteral(dialect Dialect, b []byte) string {
+	h := hex.EncodeToString(b)
+	switch dialect {
+	case PostgresDialect, CockroachDialect:
+		return `'\x` + h + `'::bytea`
+	case SQLServerDialect:
+		return `0x` + h
+	case OracleDialect:
+		return `HEXTORAW('` + h + `')`
+	}
+	return `X'` + h + `'`
+}
+
+// boolLiteral returns TRUE or FALSE where the dialect has them, otherwise 1 or 0.
+func boolLiteral(dialect Dialect, b bool) string {
+	switch dialect {
+	case MySQLDialect, SingleStoreDialect, PostgresDialect, CockroachDialect:
+		if b {
+			return `TRUE`
+		}
+		return `FALSE`
+	}
+	if b {
+		return `1`
+	}
+	return `0`
+}
+
+// timeLiteral returns t as a timestamp literal as the dialect's common drivers bind it: MySQL in UTC without an
+// offset, the others with their offset.
+func timeLiteral(dialect Dialect, t time.Time) string {
+	switch dialect {
+	case MySQLDialect, SingleStoreDialect:
+		return `'` + t.UTC().Format(`2006-01-02 15:04:05.999999`) + `'`
+	case SQLServerDialect:
+		return `'` + t.Format(`2006-01-02T15:04:05.9999999-07:00`) + `'`
+	case OracleDialect:
+		return `TIMESTAMP '` + t.Format(`2006-01-02 15:04:05.999999999 -07:00`) + `'`
+	}
+	return `'` + t.Format(`2006-01-02 15:04:05.999999999-07:00`) + `'`
+}
@@ -0,0 +1,66 @@
+package sqlinsert
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type status string
+
+var literalTime = time.Date(2022, time.March, 4, 5, 6, 7, 890000000, time.FixedZone(`EST`, -5*60*60))
+
+func TestLiteral(t *testing.T) {
+	var nilPointer *string
+	name := `it's`
+	for _, c := range []struct {
+		dialect  Dialect
+		v        interface{}
+		expected string
+	}{
+		{PostgresDialect, nil, `NULL`},
+		{PostgresDialect, nilPointer, `NULL`},
+		{PostgresDialect, &name, `'it''s'`},
+		{PostgresDialect, `back\slash`, `'back\slash'`},
+		{MySQLDialect, `it's back\slash`, `'it''s back\\slash'`},
+		{SQLServerDialect, `it's`, `N'it''s'`},
+		{OracleDialect, status(`active`), `'active'`},
+		{PostgresDialect, []byte{0xde, 0xad}, `'\xdead'::bytea`},
+		{MySQLDialect, []byte{0xde, 0xad}, `X'dead'`},
+		{SQLiteDialect, []byte{0xde, 0xad}, `X'dead'`},
+		{SQLServerDialect, []byte{0xde, 0xad}, `0xdead`},
+		{OracleDialect, []byte{0xde, 0xad}, `HEXTORAW('dead')`},
+		{PostgresDialect, []byte(nil), `NULL`},
+		{PostgresDialect, true, `TRUE`},
+		{MySQLDialect, false, `FALSE`},
+		{SQLiteDialect, true, `1`},
+		{SQLServerDialect, false, `0`},
+		{OracleDialect, true, `1`},
+		{PostgresDialect, int8(-8), `-8`},
+		{PostgresDialect, uint64(18446744073709551615), `18446744073709551615`},
+		{PostgresDialect, 1.1618, `1.1618`},
+		{PostgresDialect, float32(0.1), `0.1`},
+		{PostgresDialect, literalTime, `'2022-03-04 05:06:07.89-05:00'`},
+		{SQLiteDialect, literalTime, `'2022-03-04 05:06:07.89-05:00'`},
+		{MySQLDialect, literalTime, `'2022-03-04 10:06:07.89'`},
+		{SQLServerDialect, literalTime, `'2022-03-04T05:06:07.89-05:00'`},
+		{OracleDialect, literalTime, `TIMESTAMP '2022-03-04 05:06:07.89 -05:00'`},
+		{PostgresDialect, sql.NullString{}, `NULL`},
+		{PostgresDialect, sql.NullString{String: `x`, Valid: true}, `'x'`},
+		{PostgresDialect, sql.NullInt64{Int64: 42, Valid: true}, `42`},
+	} {
+		lit, err := literal(c.dialect, c.v)
+		if err != nil {
+			t.Fatalf(`failed at literal for %s %#v %s`, c.dialect, c.v, err)
+		}
+		if c.expected != lit {
+			t.Fatalf(`expected "%s", got "%s"`, c.expected, lit)
+		}
+	}
+}
+
+func TestLiteralUnsupportedType(t *testing.T) {
+	if _, err := literal(PostgresDialect, []string{`a`}); err == nil {
+		t.Fatal(`expected error for unsupported Go type`)
+	}
+}
@@ -0,0 +1,322 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint records the progress of a bulk load by a Loader: Offset is the number of rows of the source loaded by
+// the committed batches, and Key is the key of the last of them, if Loader.Key is set.
+type Checkpoint struct {
+	Name   string `json:"name"`
+	Offset int64  `json:"offset"`
+	Key    string `json:"key,omitempty"`
+}
+
+// CheckpointStore models the storage of the checkpoints of bulk loads by name. Load returns false if the named load
+// has no checkpoint. Save saves a checkpoint after its batch commits, so that a failure between the two loads the
+// batch again when the load resumes: at least once.
+type CheckpointStore interface {
+	Load(ctx context.Context, name string) (Checkpoint, bool, error)
+	Save(ctx context.Context, checkpoint Checkpoint) error
+}
+
+// TxCheckpointStore is a CheckpointStore that saves a checkpoint in the transaction of its batch, before it
+// commits, so that the batch and its checkpoint commit together: exactly once.
+type TxCheckpointStore interface {
+	CheckpointStore
+	SaveTx(ctx context.Context, tx *sql.Tx, checkpoint Checkpoint) error
+}
+
+// RowSource models the source of the rows of a bulk load. NextRows returns the next rows, up to n, as Insert.Data,
+// e.g., Rows or a slice of structs, or io.EOF at the end of the source.
+type RowSource interface {
+	NextRows(ctx context.Context, n int) (interface{}, error)
+}
+
+// Loader loads the rows of a RowSource into Table of DB in batches of BatchSize rows, each inserted by an Insert
+// with the Options in a transaction of its own, and records a Checkpoint of Name in Store after each batch commits.
+// An interrupted load resumes from the checkpoint: Load skips the rows of the source that were loaded, by reading
+// past Checkpoint.Offset rows, unless Key is set. Key returns the key of a row, as RowError.Data holds it, e.g., its
+// ID, for a source that starts after the Checkpoint.Key of the Checkpoint method, e.g., a query ordered by ID, in
+// place of reading past the rows loaded.
+// Load requires a TxCheckpointStore, which loads each batch exactly once, unless AtLeastOnce is true: a Store that is
+// not one, e.g., a FileCheckpointStore, loads a batch again if the load fails between its commit and its checkpoint.
+type Loader struct {
+	Name        string
+	Table       string
+	DB          *sql.DB
+	Store       CheckpointStore
+	BatchSize   int
+	Options     []Option
+	Key         func(row interface{}) string
+	AtLeastOnce bool
+}
+
+// Checkpoint returns the checkpoint of the load, or a Checkpoint at offset 0 if it has none.
+func (l *Loader) Checkpoint(ctx context.Context) (Checkpoint, error) {
+	checkpoint, ok, err := l.Store.Load(ctx, l.Name)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if !ok {
+		return Checkpoint{Name: l.Name}, nil
+	}
+	return checkpoint, nil
+}
+
+// Load loads the rows of source from the checkpoint of the load to the end of source. It returns the checkpoint of
+// the last batch committed, e.g., to report the progress of a load that fails.
+func (l *Loader) Load(ctx context.Context, source RowSource) (Checkpoint, error) {
+	if l.BatchSize < 1 {
+		return Checkpoint{}, errors.New(`sqlinsert: batch size must be at least 1`)
+	}
+	if _, ok := l.Store.(TxCheckpointStore); !ok && !l.AtLeastOnce {
+		return Checkpoint{}, fmt.Errorf(`sqlinsert: checkpoint store %T loads at least once, set AtLeastOnce to use it`,
+			l.Store)
+	}
+	checkpoint, err := l.Checkpoint(ctx)
+	if err != nil {
+		return checkpoint, err
+	}
+	if l.Key == nil {
+		for skipped := int64(0); skipped < checkpoint.Offset; {
+			n := l.BatchSize
+			if remaining := checkpoint.Offset - skipped; remaining < int64(n) {
+				n = int(remaining)
+			}
+			data, err := source.NextRows(ctx, n)
+			if err == io.EOF {
+				return checkpoint, fmt.Errorf(`sqlinsert: source ends at row %d, before checkpoint offset %d`, skipped,
+					checkpoint.Offset)
+			}
+			if err != nil {
+				return checkpoint, err
+			}
+			n = len((&Insert{Data: data}).rows())
+			if n == 0 {
+				return checkpoint, errNoRows
+			}
+			skipped += int64(n)
+		}
+	}
+	for {
+		data, err := source.NextRows(ctx, l.BatchSize)
+		if err == io.EOF {
+			return checkpoint, nil
+		}
+		if err != nil {
+			return checkpoint, err
+		}
+		next, err := l.loadBatch(ctx, data, checkpoint)
+		if err != nil {
+			return checkpoint, err
+		}
+		checkpoint = next
+	}
+}
+
+// errNoRows is the error of a RowSource that returns no rows, which must return io.EOF instead.
+var errNoRows = errors.New(`sqlinsert: source returned no rows, not io.EOF`)
+
+// loadBatch inserts the rows of data in a transaction, and saves the checkpoint that follows them.
+func (l *Loader) loadBatch(ctx context.Context, data interface{}, checkpoint Checkpoint) (Checkpoint, error) {
+	ins := NewInsert(l.Table, data, l.Options...)
+	rows := ins.rows()
+	if len(rows) == 0 {
+		return checkpoint, errNoRows
+	}
+	checkpoint.Offset += int64(len(rows))
+	if l.Key != nil {
+		checkpoint.Key = l.Key(rows[len(rows)-1].Interface())
+	}
+	tx, err := l.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return checkpoint, err
+	}
+	txStore, exactlyOnce := l.Store.(TxCheckpointStore)
+	if _, err = ins.ExecContext(ctx, tx); err == nil && exactlyOnce {
+		err = txStore.SaveTx(ctx, tx, checkpoint)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return checkpoint, err
+	}
+	if err = tx.Commit(); err != nil {
+		return checkpoint, err
+	}
+	if !exactlyOnce {
+		return checkpoint, l.Store.Save(ctx, checkpoint)
+	}
+	return checkpoint, nil
+}
+
+// SliceSource returns a RowSource of the rows of data, which is anything accepted as Insert.Data, in order.
+func SliceSource(data interface{}) RowSource {
+	if rows, ok := asRows(data); ok { // Convert Maps once, not once per batch
+		data = rows
+	}
+	ins := Insert{Data: data}
+	return &sliceSource{ins: ins, total: len(ins.rows())}
+}
+
+// sliceSource is the RowSource of SliceSource.
+type sliceSource struct {
+	ins   Insert
+	total int
+	next  int
+}
+
+// NextRows returns the next rows of the slice, up to n.
+func (s *sliceSource) NextRows(_ context.Context, n int) (interface{}, error) {
+	if s.next >= s.total {
+		return nil, io.EOF
+	}
+	indexes := make([]int, 0, n)
+	for i := s.next; i < s.total && len(indexes) < n; i++ {
+		indexes = append(indexes, i)
+	}
+	s.next += len(indexes)
+	return s.ins.selectRows(indexes), nil
+}
+
+// NextRows returns the next rows of the stream, up to n, as Rows, or io.EOF at the end of the stream. A
+// JSONDecoder is a RowSource.
+func (d *JSONDecoder) NextRows(_ context.Context, n int) (interface{}, error) {
+	rows, err := d.DecodeRows(n)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows.Values) == 0 {
+		return nil, io.EOF
+	}
+	return rows, nil
+}
+
+// FileCheckpointStore is a CheckpointStore that keeps the checkpoint of one load in a JSON file at Path, which it
+// replaces atomically. It saves the checkpoint after the batch commits, so it loads at least once: a Loader with it
+// must set AtLeastOnce.
+type FileCheckpointStore struct {
+	Path string
+}
+
+// Load reads the checkpoint from the file, if it exists and is of the named load.
+func (s *FileCheckpointStore) Load(_ context.Context, name string) (Checkpoint, bool, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	var checkpoint Checkpoint
+	if err = json.Unmarshal(b, &checkpoint); err != nil {
+		return Checkpoint{}, false, fmt.Errorf(`sqlinsert: checkpoint file %s: %w`, s.Path, err)
+	}
+	if checkpoint.Name != name {
+		return Checkpoint{}, false, fmt.Errorf(`sqlinsert: checkpoint file %s is of load %q, not %q`, s.Path,
+			checkpoint.Name, name)
+	}
+	return checkpoint, true, nil
+}
+
+// Save writes the checkpoint to a temporary file that replaces the file.
+func (s *FileCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+`.*`)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(b); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.Path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// TableCheckpointStore is a TxCheckpointStore that keeps checkpoints in Table of DB, one row per load, with the
+// columns name, row_offset, and row_key, e.g.:
+//
+//	CREATE TABLE sqlinsert_checkpoint (name VARCHAR(255) PRIMARY KEY, row_offset BIGINT NOT NULL, row_key VARCHAR(255))
+//
+// Its statements use the bind params of UseTokenType.
+type TableCheckpointStore struct {
+	Table string
+	DB    *sql.DB
+}
+
+// Load selects the checkpoint of the named load.
+func (s *TableCheckpointStore) Load(ctx context.Context, name string) (Checkpoint, bool, error) {
+	query := fmt.Sprintf(`SELECT row_offset,row_key FROM %s WHERE name = %s`, s.Table,
+		paramToken(`name`, UseTokenType, 1))
+	var (
+		checkpoint = Checkpoint{Name: name}
+		key        sql.NullString
+	)
+	err := s.DB.QueryRowContext(ctx, query, bindArg(`name`, name)).Scan(&checkpoint.Offset, &key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	checkpoint.Key = key.String
+	return checkpoint, true, nil
+}
+
+// Save saves the checkpoint on DB, outside of a transaction.
+func (s *TableCheckpointStore) Save(ctx context.Context, checkpoint Checkpoint) error {
+	return s.save(ctx, s.DB, checkpoint)
+}
+
+// SaveTx saves the checkpoint in the transaction.
+func (s *TableCheckpointStore) SaveTx(ctx context.Context, tx *sql.Tx, checkpoint Checkpoint) error {
+	return s.save(ctx, tx, checkpoint)
+}
+
+// save updates the row of the checkpoint, or inserts it if there is none.
+func (s *TableCheckpointStore) save(ctx context.Context, with InsertWith, checkpoint Checkpoint) error {
+	key := sql.NullString{String: checkpoint.Key, Valid: checkpoint.Key != ``}
+	query := fmt.Sprintf(`UPDATE %s SET row_offset = %s, row_key = %s WHERE name = %s`, s.Table,
+		paramToken(`row_offset`, UseTokenType, 1), paramToken(`row_key`, UseTokenType, 2),
+		paramToken(`name`, UseTokenType, 3))
+	result, err := with.ExecContext(ctx, query, bindArg(`row_offset`, checkpoint.Offset), bindArg(`row_key`, key),
+		bindArg(`name`, checkpoint.Name))
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	ins := Insert{Table: s.Table, Data: Rows{
+		Columns: []string{`name`, `row_offset`, `row_key`},
+		Values:  [][]interface{}{{checkpoint.Name, checkpoint.Offset, key}},
+	}}
+	_, err = ins.InsertContext(ctx, with)
+	return err
+}
+
+// bindArg returns the bind arg of the named bind param: sql.Named for the named token types, else the value.
+func bindArg(name string, value interface{}) interface{} {
+	if UseTokenType.named() {
+		return sql.Named(name, value)
+	}
+	return value
+}
@@ -0,0 +1,190 @@
+package sqlinsert
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+/* Loader */
+
+var loaderRows = Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}, {`b`}, {`c`}, {`d`}, {`e`}}}
+
+func TestLoaderResumeTableCheckpoint(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT row_offset,row_key FROM checkpoint WHERE name = ?`)).
+		WithArgs(`candy-backfill`).
+		WillReturnRows(sqlmock.NewRows([]string{`row_offset`, `row_key`}).AddRow(2, nil))
+	update := regexp.QuoteMeta(`UPDATE checkpoint SET row_offset = ?, row_key = ? WHERE name = ?`)
+	for _, batch := range []struct {
+		sql    string
+		args   []interface{}
+		offset int64
+	}{
+		{`INSERT INTO candy (id) VALUES (?),(?)`, []interface{}{`c`, `d`}, 4},
+		{`INSERT INTO candy (id) VALUES (?)`, []interface{}{`e`}, 5},
+	} {
+		mock.ExpectBegin()
+		s := regexp.QuoteMeta(batch.sql)
+		mock.ExpectPrepare(s)
+		mock.ExpectExec(s).WillReturnResult(sqlmock.NewResult(0, int64(len(batch.args))))
+		mock.ExpectExec(update).WithArgs(batch.offset, nil, `candy-backfill`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	loader := &Loader{
+		Name:      `candy-backfill`,
+		Table:     tbl,
+		DB:        db,
+		Store:     &TableCheckpointStore{Table: `checkpoint`, DB: db},
+		BatchSize: 2,
+	}
+	checkpoint, err := loader.Load(context.Background(), SliceSource(loaderRows))
+	if err != nil {
+		t.Fatalf(`failed at Load: %s`, err)
+	}
+	if checkpoint.Offset != 5 {
+		t.Fatalf(`expected offset 5, got %d`, checkpoint.Offset)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestTableCheckpointStoreInsert(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE checkpoint SET row_offset = $1, row_key = $2 WHERE name = $3`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	s := regexp.QuoteMeta(`INSERT INTO checkpoint (name,row_offset,row_key) VALUES ($1,$2,$3)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`load`, int64(3), `c`).WillReturnResult(sqlmock.NewResult(1, 1))
+	store := &TableCheckpointStore{Table: `checkpoint`, DB: db}
+	if err = store.Save(context.Background(), Checkpoint{Name: `load`, Offset: 3, Key: `c`}); err != nil {
+		t.Fatalf(`failed at Save: %s`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestLoaderResumeFileCheckpoint(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), `checkpoint.json`)}
+	loader := &Loader{
+		Name:        `candy-backfill`,
+		Table:       tbl,
+		DB:          db,
+		Store:       store,
+		BatchSize:   2,
+		Key:         func(row interface{}) string { return row.([]interface{})[0].(string) },
+		AtLeastOnce: true,
+	}
+	s := regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?),(?)`)
+	mock.ExpectBegin()
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`a`, `b`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`c`, `d`).WillReturnError(errors.New(`deploy`))
+	mock.ExpectRollback()
+	checkpoint, err := loader.Load(context.Background(), SliceSource(loaderRows))
+	if err == nil || !strings.Contains(err.Error(), `deploy`) {
+		t.Fatalf(`expected interrupted load, got "%v"`, err)
+	}
+	if checkpoint.Offset != 2 || checkpoint.Key != `b` {
+		t.Fatalf(`expected checkpoint at offset 2, key b, got %+v`, checkpoint)
+	}
+	checkpoint, err = loader.Checkpoint(context.Background())
+	if err != nil || checkpoint.Offset != 2 || checkpoint.Key != `b` {
+		t.Fatalf(`expected saved checkpoint at offset 2, key b, got %+v, %v`, checkpoint, err)
+	}
+
+	// The source resumes after the key, e.g., WHERE id > 'b'.
+	resumed := Rows{Columns: loaderRows.Columns, Values: loaderRows.Values[2:]}
+	mock.ExpectBegin()
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`c`, `d`).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	s = regexp.QuoteMeta(`INSERT INTO candy (id) VALUES (?)`)
+	mock.ExpectBegin()
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`e`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if checkpoint, err = loader.Load(context.Background(), SliceSource(resumed)); err != nil {
+		t.Fatalf(`failed at Load: %s`, err)
+	}
+	if checkpoint.Offset != 5 || checkpoint.Key != `e` {
+		t.Fatalf(`expected checkpoint at offset 5, key e, got %+v`, checkpoint)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestFileCheckpointStoreOtherLoad(t *testing.T) {
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), `checkpoint.json`)}
+	if err := store.Save(context.Background(), Checkpoint{Name: `a`, Offset: 1}); err != nil {
+		t.Fatalf(`failed at Save: %s`, err)
+	}
+	if _, _, err := store.Load(context.Background(), `b`); err == nil {
+		t.Fatal(`expected error for the checkpoint of another load`)
+	}
+}
+
+func TestLoaderAtLeastOnce(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	loader := &Loader{
+		Name:      `candy-backfill`,
+		Table:     tbl,
+		DB:        db,
+		Store:     &FileCheckpointStore{Path: filepath.Join(t.TempDir(), `checkpoint.json`)},
+		BatchSize: 2,
+	}
+	if _, err = loader.Load(context.Background(), SliceSource(loaderRows)); err == nil {
+		t.Fatal(`expected error for an at-least-once store without AtLeastOnce`)
+	}
+}
+
+// emptySource is a RowSource that returns no rows and no io.EOF.
+type emptySource struct{}
+
+func (emptySource) NextRows(context.Context, int) (interface{}, error) {
+	return Rows{Columns: []string{`id`}}, nil
+}
+
+func TestLoaderNoRows(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	store := &FileCheckpointStore{Path: filepath.Join(t.TempDir(), `checkpoint.json`)}
+	loader := &Loader{Name: `candy-backfill`, Table: tbl, DB: db, Store: store, BatchSize: 2, AtLeastOnce: true}
+	if _, err = loader.Load(context.Background(), emptySource{}); err == nil {
+		t.Fatal(`expected error for a source of no rows`)
+	}
+	if err = store.Save(context.Background(), Checkpoint{Name: `candy-backfill`, Offset: 2}); err != nil {
+		t.Fatalf(`failed at Save: %s`, err)
+	}
+	if _, err = loader.Load(context.Background(), emptySource{}); err == nil {
+		t.Fatal(`expected error for a source of no rows before the checkpoint`)
+	}
+}
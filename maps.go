@@ -0,0 +1,44 @@
+package sqlinsert
+
+import "sort"
+
+// Maps models Insert.Data for rows that are maps of column names to values, e.g., schema-on-read records that have
+// no Go struct. Columns are the column names, in order; if empty, they are the keys of all rows, sorted, and keys
+// that are not among them are ignored. A column missing from a row is NULL, or Default if MissingDefault.
+// Insert.Data also accepts a map[string]interface{} or a []map[string]interface{} as Maps with sorted columns.
+// Maps of no rows is an error of Insert.Build and Insert.InsertContext, rather than an insert of DEFAULT VALUES.
+type Maps struct {
+	Columns        []string
+	Values         []map[string]interface{}
+	MissingDefault bool
+}
+
+// Rows returns the maps as Rows, with one value per column in each row.
+func (m *Maps) Rows() *Rows {
+	columns := m.Columns
+	if len(columns) == 0 {
+		seen := make(map[string]bool)
+		for _, values := range m.Values {
+			for column := range values {
+				if !seen[column] {
+					seen[column] = true
+					columns = append(columns, column)
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+	rows := &Rows{Columns: columns, Values: make([][]interface{}, len(m.Values))}
+	for i, values := range m.Values {
+		row := make([]interface{}, len(columns))
+		for j, column := range columns {
+			if value, ok := values[column]; ok {
+				row[j] = value
+			} else if m.MissingDefault {
+				row[j] = Default
+			}
+		}
+		rows.Values[i] = row
+	}
+	return rows
+}
@@ -0,0 +1,108 @@
+package sqlinsert
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+var candyMaps = []map[string]interface{}{
+	{`id`: `a`, `candy_name`: `Gougat`},
+	{`id`: `b`, `weight_grams`: 1.5},
+}
+
+/* Maps */
+
+func TestSQLMaps(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := Insert{Table: tbl, Data: candyMaps}
+	expected := `INSERT INTO candy (candy_name,id,weight_grams) VALUES (?,?,?),(?,?,?)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestArgsMaps(t *testing.T) {
+	ins := Insert{Table: tbl, Data: candyMaps}
+	expected := []interface{}{`Gougat`, `a`, nil, nil, `b`, 1.5}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestResolvedMaps(t *testing.T) {
+	ins := NewInsert(tbl, candyMaps, Only(`id`))
+	resolved := ins.resolved()
+	rows, ok := resolved.Data.(*Rows)
+	if !ok || len(rows.Values) != 2 {
+		t.Fatalf(`expected the maps as Rows, got %v`, resolved.Data)
+	}
+	if !reflect.DeepEqual([]string{`id`}, resolved.options().only) {
+		t.Fatalf(`expected the options kept, got %v`, resolved.options().only)
+	}
+	if _, ok = ins.Data.([]map[string]interface{}); !ok {
+		t.Fatalf(`expected the data of the insert unchanged, got %v`, ins.Data)
+	}
+}
+
+func TestBuildMapsNoRows(t *testing.T) {
+	for _, data := range []interface{}{[]map[string]interface{}{}, &Maps{}, Maps{Columns: []string{`id`}}} {
+		ins := Insert{Table: tbl, Data: data}
+		if _, _, err := ins.Build(); err == nil {
+			t.Fatalf(`expected error for %#v`, data)
+		}
+		db, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf(`failed to construct SQL mock %s`, err)
+		}
+		if _, err = ins.InsertContext(context.Background(), db); err == nil {
+			t.Fatalf(`expected error for %#v`, data)
+		}
+	}
+}
+
+func TestSQLOneMap(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	ins := Insert{Table: tbl, Data: candyMaps[0]}
+	expected := `INSERT INTO candy (candy_name,id) VALUES ($1,$2)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestSQLMapsColumnsDefault(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := Insert{Table: tbl, Data: &Maps{
+		Columns:        []string{`id`, `weight_grams`},
+		Values:         candyMaps,
+		MissingDefault: true,
+	}}
+	expected := `INSERT INTO candy (id,weight_grams) VALUES (?,DEFAULT),(?,?)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{`a`, `b`, 1.5}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestBatchesMaps(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := Insert{Table: tbl, Data: candyMaps}
+	batches := ins.Batches(1)
+	if len(batches) != 2 {
+		t.Fatalf(`expected 2 batches, got %d`, len(batches))
+	}
+	expected := `INSERT INTO candy (candy_name,id,weight_grams) VALUES (?,?,?)`
+	insertSQL := batches[1].SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
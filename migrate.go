@@ -0,0 +1,319 @@
+package sqlinsert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AlterTableSQL compares the columns of sample, which may be anything accepted as Insert.Data, against the columns
+// of an existing table (see DescribeTable) and returns the ALTER TABLE statements needed for the table to accept
+// the INSERT. The statements are for review and are not executed. Column definitions derive from the struct tag as
+// in CreateTableSQL, and columns are matched by name without regard to case. The statements
+//   - add columns that are missing from the table, nullable unless the struct tag declares a default, since the
+//     existing rows have no value for them,
+//   - widen the type of columns to the derived type, e.g., VARCHAR(64) to TEXT or INT to BIGINT, and
+//   - drop NOT NULL from columns that the INSERT may leave NULL, including columns without a default that are
+//     missing from sample, except columns that the database fills: auto_increment, identity, and generated
+//     columns (see TableColumn.Extra).
+//
+// Type differences that do not widen the existing type, e.g., BIGINT to INT, are left as they are, as are
+// constraints (primary key, unique) of existing columns. A MySQL MODIFY COLUMN redefines the whole column, so it
+// repeats the default of the struct tag, and it returns an error for a column with a default that the tag does not
+// declare or with Extra attributes, e.g., auto_increment. SQLite cannot alter columns, but it also does not enforce
+// column types, so only added columns and NOT NULL are considered; dropping NOT NULL in SQLite returns an error.
+func AlterTableSQL(dialect Dialect, table string, sample interface{}, existing []TableColumn) ([]string, error) {
+	defs, err := columnDefs(dialect, recordType(sample))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		statements []string
+		matched    = make([]bool, len(existing))
+	)
+	for _, def := range defs {
+		i := findTableColumn(existing, def.name)
+		if i < 0 {
+			statements = append(statements, addColumnSQL(dialect, table, def))
+			continue
+		}
+		matched[i] = true
+		column := existing[i]
+		newType := ``
+		if dialect != SQLiteDialect && widens(column.Type, def.columnType) {
+			newType = def.columnType
+		}
+		dropNotNull := !column.Nullable && def.nullable && !column.generated()
+		if newType == `` && !dropNotNull {
+			continue
+		}
+		alter, err := alterColumnSQL(dialect, table, column, newType, dropNotNull, def.dflt)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, alter...)
+	}
+	for i, column := range existing {
+		if matched[i] || column.Nullable || column.HasDefault || column.generated() {
+			continue
+		}
+		alter, err := alterColumnSQL(dialect, table, column, ``, true, ``)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, alter...)
+	}
+	return statements, nil
+}
+
+// findTableColumn returns the index of the named column, or -1 if there is none.
+func findTableColumn(columns []TableColumn, name string) int {
+	for i, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// addColumnSQL returns the statement to add a column to a table. The column is nullable unless it has a default,
+// since a NOT NULL column without a default cannot be added to a table that has rows.
+func addColumnSQL(dialect Dialect, table string, def columnDef) string {
+	if def.dflt == `` {
+		def.nullable = true
+	}
+	switch dialect {
+	case SQLServerDialect, OracleDialect:
+		return fmt.Sprintf(`ALTER TABLE %s ADD %s`, table, def.SQL())
+	case SQLiteDialect:
+		def.unique = false // SQLite cannot add a UNIQUE column
+	}
+	return fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s`, table, def.SQL())
+}
+
+// generated reports whether the database fills the column, which needs no value and keeps its NOT NULL: a MySQL
+// auto_increment or generated column, a Postgres identity or generated column, or a SQL Server identity or
+// computed column.
+func (column TableColumn) generated() bool {
+	extra := strings.ToLower(column.Extra)
+	for _, attribute := range []string{`auto_increment`, `identity`, `generated`, `computed`} {
+		if strings.Contains(extra, attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// alterColumnSQL returns the statements to change the type of a column to newType, if not empty, and to drop its
+// NOT NULL constraint, if dropNotNull. dflt is the default that MySQL repeats in its column definition.
+func alterColumnSQL(dialect Dialect, table string, column TableColumn, newType string, dropNotNull bool,
+	dflt string) ([]string, error) {
+	columnType := column.Type
+	if newType != `` {
+		columnType = newType
+	}
+	nullability := ` NOT NULL`
+	if column.Nullable || dropNotNull {
+		nullability = ` NULL`
+	}
+	switch dialect {
+	case MySQLDialect, SingleStoreDialect:
+		if column.Extra != `` {
+			return nil, fmt.Errorf(`sqlinsert: MODIFY COLUMN would drop %s of column %s of table %s`,
+				column.Extra, column.Name, table)
+		}
+		if column.HasDefault && dflt == `` {
+			return nil, fmt.Errorf(`sqlinsert: MODIFY COLUMN would drop the default of column %s of table %s, `+
+				`declare it with the "default" tag option`, column.Name, table)
+		}
+		if dflt != `` {
+			nullability += ` DEFAULT ` + dflt
+		}
+		return []string{fmt.Sprintf(`ALTER TABLE %s MODIFY COLUMN %s %s%s`,
+			table, column.Name, columnType, nullability)}, nil
+	case PostgresDialect, CockroachDialect:
+		var statements []string
+		if newType != `` {
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s`,
+				table, column.Name, newType, column.Name, newType))
+		}
+		if dropNotNull {
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL`,
+				table, column.Name))
+		}
+		return statements, nil
+	case SQLServerDialect:
+		return []string{fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s %s%s`,
+			table, column.Name, columnType, nullability)}, nil
+	case OracleDialect:
+		var statements []string
+		if newType != `` {
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s MODIFY (%s %s)`,
+				table, column.Name, newType))
+		}
+		if dropNotNull {
+			statements = append(statements, fmt.Sprintf(`ALTER TABLE %s MODIFY (%s NULL)`, table, column.Name))
+		}
+		return statements, nil
+	case SQLiteDialect:
+		return nil, fmt.Errorf(`sqlinsert: SQLite cannot drop NOT NULL from column %s of table %s`,
+			column.Name, table)
+	}
+	return nil, fmt.Errorf(`sqlinsert: unsupported dialect %d`, dialect)
+}
+
+// typeSynonyms maps alternative spellings of column types, as reported by database catalogs, to one spelling.
+var typeSynonyms = map[string]string{
+	`character varying`: `varchar`,
+	`character`:         `char`,
+	`integer`:           `int`,
+	`int2`:              `smallint`,
+	`int4`:              `int`,
+	`int8`:              `bigint`,
+	`bool`:              `boolean`,
+	`tinyint(1)`:        `boolean`,
+	`float4`:            `real`,
+	`float8`:            `double precision`,
+	`timestamptz`:       `timestamp with time zone`,
+	`timestamp(6)`:      `timestamp`,
+	`decimal`:           `numeric`,
+}
+
+// integerDisplayWidth matches the display width that MySQL reports for integer types, e.g., the (11) of int(11).
+var integerDisplayWidth = regexp.MustCompile(`^((?:small|medium|big)?int)\(\d+\)`)
+
+// canonicalType normalizes a column type for comparison: lowercase, single spaces, no spaces in the arguments,
+// no MySQL integer display width, and one spelling of synonymous types.
+func canonicalType(columnType string) string {
+	t := strings.ToLower(strings.Join(strings.Fields(columnType), ` `))
+	base, args := t, ``
+	if i := strings.Index(t, `(`); i >= 0 {
+		base, args = strings.TrimSpace(t[:i]), strings.Replace(t[i:], ` `, ``, -1)
+		if j := strings.Index(args, `)`); j >= 0 && j < len(args)-1 {
+			args = args[:j+1] + ` ` + args[j+1:] // e.g., "bigint(20) unsigned"
+		}
+	}
+	if synonym, ok := typeSynonyms[base+args]; ok {
+		return synonym
+	}
+	if synonym, ok := typeSynonyms[base]; ok {
+		base = synonym
+	}
+	return integerDisplayWidth.ReplaceAllString(base+args, `$1`)
+}
+
+// typeWidth places a canonical column type in a family of types that widen one another, e.g., integer, by its width
+// in the family: bytes, characters, or unbounded.
+type typeWidth struct {
+	family string
+	width  int
+}
+
+// unbounded is the width of types without a length limit, e.g., TEXT.
+const unbounded = -1
+
+// typeWidths maps canonical column types, and the bases of types that take a length, to their family and width.
+// A width of 0 takes the width from the length, e.g., the 64 of varchar(64).
+var typeWidths = map[string]typeWidth{
+	`smallint`:         {`integer`, 2},
+	`mediumint`:        {`integer`, 3},
+	`int`:              {`integer`, 4},
+	`bigint`:           {`integer`, 8},
+	`real`:             {`float`, 4},
+	`float`:            {`float`, 4},
+	`binary_float`:     {`float`, 4},
+	`double`:           {`float`, 8},
+	`double precision`: {`float`, 8},
+	`binary_double`:    {`float`, 8},
+	`varchar`:          {`string`, 0},
+	`varchar2`:         {`string`, 0},
+	`varchar(max)`:     {`string`, unbounded},
+	`text`:             {`string`, unbounded},
+	`mediumtext`:       {`string`, unbounded},
+	`longtext`:         {`string`, unbounded},
+	`clob`:             {`string`, unbounded},
+	`nvarchar`:         {`nstring`, 0},
+	`nvarchar2`:        {`nstring`, 0},
+	`nvarchar(max)`:    {`nstring`, unbounded},
+	`nclob`:            {`nstring`, unbounded},
+	`varbinary`:        {`binary`, 0},
+	`raw`:              {`binary`, 0},
+	`varbinary(max)`:   {`binary`, unbounded},
+	`blob`:             {`binary`, unbounded},
+	`mediumblob`:       {`binary`, unbounded},
+	`longblob`:         {`binary`, unbounded},
+	`bytea`:            {`binary`, unbounded},
+}
+
+// widens reports whether changing a column from type from to type to widens it, so that the column keeps every
+// value that it holds: a wider type of the same family, a greater length or precision of the same type, e.g.,
+// datetime to datetime(6), or a numeric type with at least as many integer and fraction digits.
+func widens(from, to string) bool {
+	from, to = canonicalType(from), canonicalType(to)
+	if from == to {
+		return false
+	}
+	fromBase, fromArgs := splitType(from)
+	toBase, toArgs := splitType(to)
+	if fromBase == `numeric` && toBase == `numeric` && len(fromArgs) > 0 && len(toArgs) > 0 {
+		fromScale, toScale := 0, 0
+		if len(fromArgs) > 1 {
+			fromScale = fromArgs[1]
+		}
+		if len(toArgs) > 1 {
+			toScale = toArgs[1]
+		}
+		return toArgs[0]-toScale >= fromArgs[0]-fromScale && toScale >= fromScale
+	}
+	fromWidth, fromOK := lookupWidth(from, fromBase, fromArgs)
+	toWidth, toOK := lookupWidth(to, toBase, toArgs)
+	if fromOK && toOK {
+		if fromWidth.family != toWidth.family || fromWidth.width == unbounded {
+			return false
+		}
+		return toWidth.width == unbounded || toWidth.width > fromWidth.width
+	}
+	if fromBase != toBase || len(fromArgs) > 1 || len(toArgs) != 1 {
+		return false
+	}
+	return len(fromArgs) == 0 || toArgs[0] > fromArgs[0]
+}
+
+// lookupWidth returns the typeWidth of a canonical column type, with the width of a type that takes a length
+// taken from its length.
+func lookupWidth(columnType, base string, args []int) (typeWidth, bool) {
+	if w, ok := typeWidths[columnType]; ok && w.width != 0 {
+		return w, true
+	}
+	w, ok := typeWidths[base]
+	if !ok {
+		return typeWidth{}, false
+	}
+	if w.width == 0 {
+		if len(args) != 1 {
+			return typeWidth{}, false
+		}
+		w.width = args[0]
+	}
+	return w, true
+}
+
+// splitType splits a canonical column type into its base and its numeric arguments, e.g., numeric and 9, 3 of
+// numeric(9,3). A type with arguments that are not numbers, e.g., varchar(max), is all base.
+func splitType(columnType string) (string, []int) {
+	i := strings.Index(columnType, `(`)
+	j := strings.Index(columnType, `)`)
+	if i < 0 || j < i || j != len(columnType)-1 {
+		return columnType, nil
+	}
+	var args []int
+	for _, arg := range strings.Split(columnType[i+1:j], `,`) {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return columnType, nil
+		}
+		args = append(args, n)
+	}
+	return columnType[:i], args
+}
@@ -0,0 +1,208 @@
+package sqlinsert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/* AlterTableSQL */
+
+// existingCandyTable is table candy as created for candyInsert, but with a narrower candy_name column, no
+// description column, a NOT NULL manufacturer column, and an extra NOT NULL column without a default.
+var existingCandyTable = []TableColumn{
+	{Name: `id`, Type: `character varying(36)`},
+	{Name: `candy_name`, Type: `character varying(64)`},
+	{Name: `form_factor`, Type: `text`, Nullable: true},
+	{Name: `manufacturer`, Type: `text`},
+	{Name: `weight_grams`, Type: `numeric(9, 3)`},
+	{Name: `stock`, Type: `integer`, HasDefault: true},
+	{Name: `image`, Type: `bytea`},
+	{Name: `ts`, Type: `timestamp with time zone`, HasDefault: true},
+	{Name: `batch`, Type: `integer`},
+}
+
+func TestAlterTableSQLPostgres(t *testing.T) {
+	expected := []string{
+		`ALTER TABLE candy ALTER COLUMN candy_name TYPE TEXT USING candy_name::TEXT`,
+		`ALTER TABLE candy ADD COLUMN description TEXT`,
+		`ALTER TABLE candy ALTER COLUMN manufacturer DROP NOT NULL`,
+		`ALTER TABLE candy ALTER COLUMN batch DROP NOT NULL`,
+	}
+	statements, err := AlterTableSQL(PostgresDialect, tbl, candyTable{}, existingCandyTable)
+	if err != nil {
+		t.Fatalf(`failed at AlterTableSQL %s`, err)
+	}
+	if !reflect.DeepEqual(expected, statements) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, statements)
+	}
+}
+
+func TestAlterTableSQLMySQL(t *testing.T) {
+	existing := []TableColumn{
+		{Name: `id`, Type: `varchar(36)`},
+		{Name: `candy_name`, Type: `varchar(255)`},
+		{Name: `form_factor`, Type: `varchar(255)`, Nullable: true},
+		{Name: `description`, Type: `varchar(255)`, Nullable: true},
+		{Name: `manufacturer`, Type: `varchar(255)`, Nullable: true},
+		{Name: `weight_grams`, Type: `decimal(9,3)`},
+		{Name: `stock`, Type: `int(11)`, HasDefault: true},
+		{Name: `image`, Type: `blob`},
+		{Name: `ts`, Type: `datetime`, HasDefault: true},
+	}
+	expected := []string{
+		`ALTER TABLE candy MODIFY COLUMN ts DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+	}
+	statements, err := AlterTableSQL(MySQLDialect, tbl, candyTable{}, existing)
+	if err != nil {
+		t.Fatalf(`failed at AlterTableSQL %s`, err)
+	}
+	if !reflect.DeepEqual(expected, statements) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, statements)
+	}
+}
+
+func TestAlterTableSQLMySQLExtra(t *testing.T) {
+	existing := []TableColumn{
+		{Name: `id`, Type: `varchar(36)`},
+		{Name: `candy_name`, Type: `varchar(255)`},
+		{Name: `ts`, Type: `datetime`, HasDefault: true, Extra: `on update CURRENT_TIMESTAMP`},
+	}
+	if _, err := AlterTableSQL(MySQLDialect, tbl, candyTable{}, existing); err == nil {
+		t.Fatal(`expected error for modifying a column with extra attributes`)
+	}
+	existing[2] = TableColumn{Name: `ts`, Type: `datetime`, HasDefault: true}
+	existing = append(existing, TableColumn{Name: `seq`, Type: `bigint`, Extra: `auto_increment`})
+	statements, err := AlterTableSQL(MySQLDialect, tbl, candyTable{}, existing)
+	if err != nil {
+		t.Fatalf(`failed at AlterTableSQL %s`, err)
+	}
+	for _, statement := range statements {
+		if strings.Contains(statement, `seq`) {
+			t.Fatalf(`expected no statement for auto_increment column, got "%s"`, statement)
+		}
+	}
+}
+
+func TestAlterTableSQLNarrowing(t *testing.T) {
+	existing := []TableColumn{
+		{Name: `id`, Type: `text`},
+		{Name: `candy_name`, Type: `text`},
+		{Name: `form_factor`, Type: `text`, Nullable: true},
+		{Name: `description`, Type: `text`, Nullable: true},
+		{Name: `manufacturer`, Type: `text`, Nullable: true},
+		{Name: `weight_grams`, Type: `numeric(12, 4)`},
+		{Name: `stock`, Type: `bigint`, HasDefault: true},
+		{Name: `image`, Type: `bytea`},
+		{Name: `ts`, Type: `timestamp with time zone`, HasDefault: true},
+	}
+	statements, err := AlterTableSQL(PostgresDialect, tbl, candyTable{}, existing)
+	if err != nil {
+		t.Fatalf(`failed at AlterTableSQL %s`, err)
+	}
+	if len(statements) != 0 {
+		t.Fatalf(`expected no statements, got "%v"`, statements)
+	}
+}
+
+func TestWidens(t *testing.T) {
+	cases := []struct {
+		from, to string
+		expected bool
+	}{
+		{`int(11)`, `BIGINT`, true},
+		{`bigint`, `INT`, false},
+		{`character varying(64)`, `TEXT`, true},
+		{`text`, `VARCHAR(255)`, false},
+		{`varchar(255)`, `VARCHAR(64)`, false},
+		{`datetime`, `DATETIME(6)`, true},
+		{`numeric(9, 3)`, `NUMERIC(12,3)`, true},
+		{`numeric(9, 3)`, `NUMERIC(9,2)`, false},
+		{`real`, `DOUBLE PRECISION`, true},
+		{`text`, `INTEGER`, false},
+	}
+	for _, c := range cases {
+		if actual := widens(c.from, c.to); actual != c.expected {
+			t.Fatalf(`expected %v for %s to %s, got %v`, c.expected, c.from, c.to, actual)
+		}
+	}
+}
+
+func TestAlterTableSQLUpToDate(t *testing.T) {
+	existing := []TableColumn{
+		{Name: `ID`, Type: `VARCHAR2(255)`},
+		{Name: `CANDY_NAME`, Type: `VARCHAR2(255)`},
+		{Name: `FORM_FACTOR`, Type: `VARCHAR2(255)`},
+		{Name: `DESCRIPTION`, Type: `VARCHAR2(255)`},
+		{Name: `MANUFACTURER`, Type: `VARCHAR2(255)`},
+		{Name: `WEIGHT_GRAMS`, Type: `BINARY_DOUBLE`},
+		{Name: `TS`, Type: `TIMESTAMP(6)`},
+	}
+	statements, err := AlterTableSQL(OracleDialect, tbl, recValue, existing)
+	if err != nil {
+		t.Fatalf(`failed at AlterTableSQL %s`, err)
+	}
+	if len(statements) != 0 {
+		t.Fatalf(`expected no statements, got "%v"`, statements)
+	}
+}
+
+func TestAlterTableSQLSQLiteNotNull(t *testing.T) {
+	if _, err := AlterTableSQL(SQLiteDialect, tbl, candyTable{}, existingCandyTable); err == nil {
+		t.Fatal(`expected error for dropping NOT NULL in SQLite`)
+	}
+}
+
+func TestAlterTableSQLAddColumn(t *testing.T) {
+	existing := []TableColumn{
+		{Name: `id`, Type: `character varying(36)`},
+		{Name: `form_factor`, Type: `text`, Nullable: true},
+		{Name: `description`, Type: `text`, Nullable: true},
+		{Name: `manufacturer`, Type: `text`, Nullable: true},
+		{Name: `weight_grams`, Type: `numeric(9,3)`},
+		{Name: `image`, Type: `bytea`},
+		{Name: `ts`, Type: `timestamp with time zone`, HasDefault: true},
+	}
+	expected := []string{
+		`ALTER TABLE candy ADD COLUMN candy_name TEXT UNIQUE`,
+		`ALTER TABLE candy ADD COLUMN stock INTEGER NOT NULL DEFAULT 0`,
+	}
+	statements, err := AlterTableSQL(PostgresDialect, tbl, candyTable{}, existing)
+	if err != nil {
+		t.Fatalf(`failed at AlterTableSQL %s`, err)
+	}
+	if !reflect.DeepEqual(expected, statements) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, statements)
+	}
+}
+
+func TestAlterTableSQLGenerated(t *testing.T) {
+	type sample struct {
+		FormFactor *string `col:"form_factor"`
+	}
+	for _, tc := range []struct {
+		dialect  Dialect
+		existing []TableColumn
+	}{
+		{PostgresDialect, []TableColumn{
+			{Name: `seq`, Type: `bigint`, Extra: `identity`},
+			{Name: `total`, Type: `numeric`, Extra: `generated`},
+			{Name: `form_factor`, Type: `text`, Extra: `identity`},
+		}},
+		{SQLServerDialect, []TableColumn{
+			{Name: `seq`, Type: `bigint`, Extra: `identity`},
+			{Name: `total`, Type: `numeric`, Extra: `computed`},
+		}},
+	} {
+		statements, err := AlterTableSQL(tc.dialect, tbl, sample{}, tc.existing)
+		if err != nil {
+			t.Fatalf(`failed at AlterTableSQL %s`, err)
+		}
+		for _, statement := range statements {
+			if strings.Contains(statement, `seq`) || strings.Contains(statement, `total`) ||
+				strings.Contains(statement, `NULL`) {
+				t.Fatalf(`expected no statement for a column that the database fills, got "%s"`, statement)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Observer models functionality to log, measure, or trace the SQL INSERT statements executed by Insert.Insert and
+// Insert.InsertContext. OnStart is called before the statement is prepared and returns the context for the rest of
+// the insert, e.g., with a tracing span. OnFinish is called with that context after the statement executes or fails.
+type Observer interface {
+	OnStart(ctx context.Context, event StartEvent) context.Context
+	OnFinish(ctx context.Context, event FinishEvent)
+}
+
+// StartEvent describes a SQL INSERT statement about to be executed. Rows is 0 for InsertSelect.
+// Args holds the bind args only if ObserveArgs is true, so that row data (possibly PII) is not logged by default.
+type StartEvent struct {
+	Query   string
+	Rows    int
+	NumArgs int
+	Args    []interface{}
+}
+
+// FinishEvent describes a SQL INSERT statement that was executed or that failed.
+// RowsAffected is -1 if the statement failed or the driver does not report it.
+type FinishEvent struct {
+	StartEvent
+	Duration     time.Duration
+	RowsAffected int64
+	Err          error
+}
+
+// WithObserver sets the Observer of the Insert, in place of UseObserver.
+func WithObserver(observer Observer) Option {
+	return func(ins *Insert) {
+		ins.configure().observer = observer
+	}
+}
+
+// UseObserver specifies the Observer for every Insert that has none of its own. Default is nil, which observes
+// nothing.
+var UseObserver Observer
+
+// ObserveArgs specifies whether StartEvent.Args includes the bind args. Default is false.
+var ObserveArgs = false
+
+// observe calls OnStart of observer, or of UseObserver if observer is nil, and returns the context for the insert
+// and a function that calls OnFinish.
+func observe(ctx context.Context, observer Observer, query string, args []interface{}, rows int) (context.Context,
+	func(sql.Result, error)) {
+	if observer == nil {
+		observer = UseObserver
+	}
+	if observer == nil {
+		return ctx, func(sql.Result, error) {}
+	}
+	start := StartEvent{Query: query, Rows: rows, NumArgs: len(args)}
+	if ObserveArgs {
+		start.Args = args
+	}
+	ctx = observer.OnStart(ctx, start)
+	began := time.Now()
+	return ctx, func(result sql.Result, err error) {
+		finish := FinishEvent{StartEvent: start, Duration: time.Since(began), RowsAffected: -1, Err: err}
+		if err == nil {
+			if n, rowsErr := result.RowsAffected(); rowsErr == nil {
+				finish.RowsAffected = n
+			}
+		}
+		observer.OnFinish(ctx, finish)
+	}
+}
+
+// Recorder is an Observer that keeps the events of every insert in memory, e.g., for tests.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	events []FinishEvent
+}
+
+// OnStart returns ctx unchanged. The start of an insert is recorded with its finish.
+func (r *Recorder) OnStart(ctx context.Context, _ StartEvent) context.Context {
+	return ctx
+}
+
+// OnFinish records the event.
+func (r *Recorder) OnFinish(_ context.Context, event FinishEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// Events returns the recorded events in the order the inserts finished.
+func (r *Recorder) Events() []FinishEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]FinishEvent(nil), r.events...)
+}
+
+// Reset discards the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
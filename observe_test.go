@@ -0,0 +1,74 @@
+package sqlinsert
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"testing"
+)
+
+/* Observer */
+
+// TestInsertContextObserver tests that the Observer of the insert sees query, rows, args count, and rows affected
+func TestInsertContextObserver(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	recorder := &Recorder{}
+	ins := NewInsert(tbl, fiveRecsPointers, WithObserver(recorder))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(ins.SQL())
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnResult(sqlmock.NewResult(5, 5))
+	_, err = ins.InsertContext(context.Background(), db)
+	if err != nil {
+		t.Fatalf(`failed at InsertContext, could not execute SQL statement %s`, err)
+	}
+	events := recorder.Events()
+	if len(events) != 1 {
+		t.Fatalf(`expected 1 event, got %d`, len(events))
+	}
+	event := events[0]
+	if event.Query != ins.SQL() || event.Rows != 5 || event.NumArgs != 35 || event.RowsAffected != 5 ||
+		event.Err != nil {
+		t.Fatalf(`unexpected event %+v`, event)
+	}
+	if event.Args != nil {
+		t.Fatalf(`expected no args by default, got %v`, event.Args)
+	}
+}
+
+// TestInsertUseObserver tests the global Observer, opt-in args, and failed inserts
+func TestInsertUseObserver(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	recorder := &Recorder{}
+	UseObserver, ObserveArgs = recorder, true
+	defer func() {
+		UseObserver, ObserveArgs = nil, false
+	}()
+	ins := Insert{Table: tbl, Data: recValue}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(ins.SQL())
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnError(errors.New(`duplicate key`))
+	if _, err = ins.Insert(db); err == nil {
+		t.Fatal(`expected error from Exec`)
+	}
+	events := recorder.Events()
+	if len(events) != 1 {
+		t.Fatalf(`expected 1 event, got %d`, len(events))
+	}
+	event := events[0]
+	if event.Err == nil || event.RowsAffected != -1 || len(event.Args) != 7 {
+		t.Fatalf(`unexpected event %+v`, event)
+	}
+	recorder.Reset()
+	if len(recorder.Events()) != 0 {
+		t.Fatal(`expected no events after Reset`)
+	}
+}
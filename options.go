@@ -0,0 +1,158 @@
+package sqlinsert
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Option configures an Insert, e.g., to insert only some of its columns. Options are applied by NewInsert and
+// Insert.With.
+type Option func(ins *Insert)
+
+// insertOptions holds the settings of the Options of an Insert.
+type insertOptions struct {
+	dialect     Dialect
+	tokenType   TokenType
+	tokenTyped  bool
+	observer    Observer
+	only        []string
+	exclude     []string
+	exprs       map[string]string
+	defaultZero bool
+	omit        []string
+	groupTypes  bool
+	tables      map[reflect.Type]string
+
+	isolateErrors bool
+	deadLetters   *deadLetters
+	rowIndex      []int
+
+	ignoreConflicts bool
+	conflictTarget  []string
+	statementVerb   string
+}
+
+// options returns the options of the Insert, or none for an Insert without them.
+func (ins *Insert) options() *insertOptions {
+	if ins.opts != nil {
+		return ins.opts
+	}
+	return &insertOptions{}
+}
+
+// configure returns the options of the Insert for an Option to set. They are copied, since copies of the Insert
+// share them.
+func (ins *Insert) configure() *insertOptions {
+	options := *ins.options()
+	ins.opts = &options
+	return &options
+}
+
+// derive returns a copy of the Insert, with its options, of data, whose rows are the rows of the original
+// Insert.Data that rowIndex numbers (see rowNumber).
+func (ins *Insert) derive(data interface{}, rowIndex []int) Insert {
+	derived := *ins
+	derived.Data = data
+	derived.configure().rowIndex = rowIndex
+	return derived
+}
+
+// NewInsert returns an Insert of data into table, configured by the options.
+func NewInsert(table string, data interface{}, opts ...Option) *Insert {
+	return (&Insert{Table: table, Data: data}).With(opts...)
+}
+
+// With applies the options to the Insert and returns it.
+func (ins *Insert) With(opts ...Option) *Insert {
+	for _, opt := range opts {
+		opt(ins)
+	}
+	return ins
+}
+
+// Only restricts the columns of the Insert to the named columns, e.g., for a partial-table insert. Columns keep
+// the order of Insert.Data. A name that is not a column of Insert.Data is an error.
+func Only(columns ...string) Option {
+	return func(ins *Insert) {
+		options := ins.configure()
+		options.only = append(options.only, columns...)
+	}
+}
+
+// Exclude leaves the named columns out of the Insert, e.g., columns that the database fills, such as serial IDs
+// or generated columns. A name that is not a column of Insert.Data is an error.
+func Exclude(columns ...string) Option {
+	return func(ins *Insert) {
+		options := ins.configure()
+		options.exclude = append(options.exclude, columns...)
+	}
+}
+
+// columnIndexes returns the indexes of the columns of Insert.Data that remain after Only and Exclude, and the
+// columns that DefaultGroups leaves out, in order, or nil if none of them is set. Unknown names match no column;
+// checkColumns reports them.
+func (ins *Insert) columnIndexes() []int {
+	options := ins.options()
+	if len(options.only) == 0 && len(options.exclude) == 0 && len(options.omit) == 0 {
+		return nil
+	}
+	indexes := make([]int, 0)
+	for i, name := range ins.allColumnNames() {
+		if options.selects(name) && indexOf(options.omit, name) < 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// selects reports whether the named column remains after Only and Exclude.
+func (options *insertOptions) selects(name string) bool {
+	return (len(options.only) == 0 || indexOf(options.only, name) >= 0) && indexOf(options.exclude, name) < 0
+}
+
+// checkColumns returns the error of an unknown column in Only or Exclude, or of no columns to insert.
+func (ins *Insert) checkColumns() error {
+	options := ins.options()
+	if len(options.only) == 0 && len(options.exclude) == 0 {
+		return nil
+	}
+	columnNames := ins.allColumnNames()
+	for _, names := range [][]string{options.only, options.exclude} {
+		for _, name := range names {
+			if indexOf(columnNames, name) < 0 {
+				return fmt.Errorf(`sqlinsert: unknown column %q`, name)
+			}
+		}
+	}
+	for _, name := range columnNames {
+		if options.selects(name) {
+			return nil
+		}
+	}
+	return errors.New(`sqlinsert: no columns to insert`)
+}
+
+// selectStrings returns the elements of ss at the indexes, or ss if indexes is nil.
+func selectStrings(ss []string, indexes []int) []string {
+	if indexes == nil {
+		return ss
+	}
+	selected := make([]string, len(indexes))
+	for i, index := range indexes {
+		selected[i] = ss[index]
+	}
+	return selected
+}
+
+// selectValues returns the elements of values at the indexes, or values if indexes is nil.
+func selectValues(values []interface{}, indexes []int) []interface{} {
+	if indexes == nil {
+		return values
+	}
+	selected := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		selected[i] = values[index]
+	}
+	return selected
+}
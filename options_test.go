@@ -0,0 +1,129 @@
+package sqlinsert
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"testing"
+)
+
+/* Options */
+
+// - Only
+
+func TestSQLOnly(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, []candyInsert{recValue, recValue}, Only(`candy_name`, `id`))
+	expected := `INSERT INTO candy (id,candy_name) VALUES (?,?),(?,?)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{recValue.Id, recValue.Name, recValue.Id, recValue.Name}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestSQLOnlyNewData(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := NewInsert(tbl, recValue, Only(`id`))
+	ins.Data = []candyInsert{recValue, recValue}
+	expected := `INSERT INTO candy (id) VALUES (?),(?)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	if _, ok := ins.Data.([]candyInsert); !ok {
+		t.Fatalf(`expected the data set, got %T`, ins.Data)
+	}
+}
+
+func TestSQLOnlyRows(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	ins := NewInsert(tbl, candyRows, Only(`candy_name`))
+	expected := `INSERT INTO candy (candy_name) VALUES ($1),($2),($3)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{`Gougat`, nil, `Toffee`}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+// - Exclude
+
+func TestSQLExclude(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := Insert{Table: tbl, Data: &recValue}
+	ins.With(Exclude(`id`, `ts`))
+	expected := `INSERT INTO candy (candy_name,form_factor,description,manufacturer,weight_grams) VALUES (?,?,?,?,?)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{recValue.Name, recValue.FormFactor, recValue.Description, recValue.Mfr,
+		recValue.Weight}
+	args := ins.Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestDebugSQLOnlyExclude(t *testing.T) {
+	ins := NewInsert(tbl, recValue, Only(`id`, `candy_name`, `weight_grams`), Exclude(`candy_name`))
+	expected := `INSERT INTO candy (id,weight_grams) VALUES ('c0600afd-78a7-4a1a-87c5-1bc48cafd14e',1.1618)`
+	debugSQL := ins.DebugSQL(PostgresDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+// - Errors
+
+func TestBuildUnknownColumn(t *testing.T) {
+	for _, opt := range []Option{Only(`id`, `nope`), Exclude(`nope`)} {
+		ins := NewInsert(tbl, recValue, opt)
+		_, _, err := ins.Build()
+		expected := `sqlinsert: unknown column "nope"`
+		if err == nil || expected != err.Error() {
+			t.Fatalf(`expected "%s", got "%v"`, expected, err)
+		}
+	}
+}
+
+func TestBuildNoColumns(t *testing.T) {
+	ins := NewInsert(tbl, candyRows, Exclude(`id`, `candy_name`))
+	if _, _, err := ins.Build(); err == nil {
+		t.Fatal(`expected error for no columns`)
+	}
+}
+
+func TestSQLUnknownColumn(t *testing.T) {
+	ins := NewInsert(tbl, recValue, Only(`nope`))
+	_ = ins.SQL()
+	if _, _, err := ins.Build(); err == nil {
+		t.Fatal(`expected error for unknown column`)
+	}
+	if args := ins.Args(); args != nil {
+		t.Fatalf(`expected no args, got "%v"`, args)
+	}
+}
+
+func TestInsertContextUnknownColumn(t *testing.T) {
+	ins := NewInsert(tbl, recValue, Exclude(`nope`))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	if _, err = ins.InsertContext(context.Background(), db); err == nil {
+		t.Fatal(`expected error for unknown column`)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`expected no SQL, got %s`, err)
+	}
+}
@@ -0,0 +1,171 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// GroupTypes lets Insert.Data be a slice, e.g., []interface{}, of rows of several struct types, which are inserted
+// by TypeGroups as one statement per struct type: into the table that tables maps the type to, or Insert.Table.
+// For example:
+//
+//	ins := sqlinsert.NewInsert(`candy`, events, sqlinsert.GroupTypes(map[reflect.Type]string{
+//		reflect.TypeOf(Order{}):  `orders`,
+//		reflect.TypeOf(Refund{}): `refunds`,
+//	}))
+//
+// Without it, rows of different struct types are an error. If a statement fails, Insert.ExecContext returns its error
+// with the InsertResult of the statements before it.
+func GroupTypes(tables map[reflect.Type]string) Option {
+	return func(ins *Insert) {
+		options := ins.configure()
+		options.groupTypes = true
+		options.tables = make(map[reflect.Type]string, len(tables))
+		for t, table := range tables {
+			options.tables[t] = table
+		}
+	}
+}
+
+// TypeGroups splits an insert of rows of several struct types, allowed by GroupTypes, into one insert per struct
+// type, in order of first appearance, each into the table that GroupTypes maps the type to, or Insert.Table. Rows
+// keep their order within each insert. An insert of rows of one type, or without GroupTypes, yields the insert
+// itself. It returns the error of a nil row or a row that is not a struct.
+func (ins *Insert) TypeGroups() ([]Insert, error) {
+	data := reflect.ValueOf(ins.Data)
+	if !ins.options().groupTypes || data.Kind() != reflect.Slice {
+		return []Insert{*ins}, nil
+	}
+	var (
+		types []reflect.Type
+		rows  = make(map[reflect.Type][]int)
+	)
+	for i := 0; i < data.Len(); i++ {
+		t, err := structType(data.Index(i), i)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := rows[t]; !ok {
+			types = append(types, t)
+		}
+		rows[t] = append(rows[t], i)
+	}
+	if len(types) < 2 {
+		return []Insert{*ins}, nil
+	}
+	inserts := make([]Insert, len(types))
+	for i, t := range types {
+		group := ins.subset(rows[t])
+		options := group.configure()
+		options.groupTypes = false
+		options.tables = nil
+		if table, ok := ins.options().tables[t]; ok {
+			group.Table = table
+		}
+		inserts[i] = group
+	}
+	return inserts, nil
+}
+
+// checkData returns an error unless Insert.Data is Rows or Maps of at least one row and column, with one value per
+// column in each row, a struct or non-nil struct pointer, or a non-empty slice of them, e.g., []interface{}, whose
+// rows are all of one struct type.
+func (ins *Insert) checkData() error {
+	if rows, ok := asRows(ins.Data); ok {
+		if len(rows.Values) == 0 {
+			return fmt.Errorf(`sqlinsert: %T has no rows`, ins.Data)
+		}
+		if len(rows.Columns) == 0 {
+			return fmt.Errorf(`sqlinsert: %T has no columns`, ins.Data)
+		}
+		for i, values := range rows.Values {
+			if len(values) != len(rows.Columns) {
+				return fmt.Errorf(`sqlinsert: row %d has %d values, not one per column (%d)`, ins.rowNumber(i),
+					len(values), len(rows.Columns))
+			}
+		}
+		return nil
+	}
+	data := reflect.ValueOf(ins.Data)
+	if data.Kind() != reflect.Slice {
+		_, err := structType(data, -1)
+		return err
+	}
+	if data.Len() == 0 {
+		return fmt.Errorf(`sqlinsert: %T has no rows`, ins.Data)
+	}
+	var first reflect.Type
+	for i := 0; i < data.Len(); i++ {
+		t, err := structType(data.Index(i), i)
+		if err != nil {
+			return err
+		}
+		if first == nil {
+			first = t
+		} else if t != first {
+			if ins.options().groupTypes {
+				return fmt.Errorf(`sqlinsert: row %d is %s, not %s like row 0, use TypeGroups`, i, t, first)
+			}
+			return fmt.Errorf(`sqlinsert: row %d is %s, not %s like row 0`, i, t, first)
+		}
+	}
+	return nil
+}
+
+// structType returns the struct type of row i of Insert.Data, or of Insert.Data itself if i is -1, dereferencing
+// pointers and interfaces, or the error of a nil row or a row that is not a struct.
+func structType(v reflect.Value, i int) (reflect.Type, error) {
+	row := `Data`
+	if i >= 0 {
+		row = fmt.Sprintf(`row %d`, i)
+	}
+	if !v.IsValid() {
+		return nil, fmt.Errorf(`sqlinsert: %s is nil`, row)
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() && v.Kind() == reflect.Interface {
+			return nil, fmt.Errorf(`sqlinsert: %s is nil`, row)
+		} else if v.IsNil() {
+			return nil, fmt.Errorf(`sqlinsert: %s is a nil %s`, row, v.Type())
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(`sqlinsert: %s is %s, not a struct`, row, v.Type())
+	}
+	return v.Type(), nil
+}
+
+// insertTypeGroups is insert for GroupTypes: it inserts the TypeGroups in turn, returning the statement of the last
+// one and the sum of their InsertResults. If a group fails, the sum is of the groups inserted before it, so that the
+// caller knows what was inserted.
+func (ins *Insert) insertTypeGroups(ctx context.Context, with InsertWith) (*sql.Stmt, *InsertResult, error) {
+	groups, err := ins.TypeGroups()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		stmt   *sql.Stmt
+		total  = &InsertResult{}
+		failed RowErrors
+	)
+	for _, group := range groups {
+		group.configure().groupTypes = false
+		var result *InsertResult
+		stmt, result, err = group.insert(ctx, with)
+		if result != nil {
+			total.add(result)
+		}
+		if rowErrs, ok := err.(RowErrors); ok && result != nil {
+			failed = append(failed, rowErrs...)
+		} else if err != nil {
+			return stmt, total, err
+		}
+	}
+	if len(failed) > 0 {
+		return stmt, total, failed
+	}
+	return stmt, total, nil
+}
@@ -0,0 +1,145 @@
+package sqlinsert
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type refundInsert struct {
+	Id     string `col:"id"`
+	Amount int    `col:"amount"`
+}
+
+/* Mixed rows */
+
+// - []interface{}
+
+func TestSQLInterfaceRows(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rec := recValue
+	ins := NewInsert(tbl, []interface{}{recValue, &rec}, Only(`id`, `candy_name`))
+	expected := `INSERT INTO candy (id,candy_name) VALUES (?,?),(?,?)`
+	if insertSQL := ins.SQL(); expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{recValue.Id, recValue.Name, rec.Id, rec.Name}
+	if args := ins.Args(); !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expectedArgs, args)
+	}
+}
+
+func TestBuildBadRows(t *testing.T) {
+	var nilRec *candyInsert
+	for _, tc := range []struct {
+		data     interface{}
+		expected string
+	}{
+		{[]*candyInsert{&recValue, nil}, `row 1 is a nil *sqlinsert.candyInsert`},
+		{[]interface{}{recValue, nil}, `row 1 is nil`},
+		{[]interface{}{recValue, refundInsert{}}, `row 1 is sqlinsert.refundInsert, not sqlinsert.candyInsert like row 0`},
+		{[]interface{}{recValue, `candy`}, `row 1 is string, not a struct`},
+		{[]interface{}{}, `[]interface {} has no rows`},
+		{[]candyInsert{}, `[]sqlinsert.candyInsert has no rows`},
+		{[]*candyInsert{}, `[]*sqlinsert.candyInsert has no rows`},
+		{nilRec, `Data is a nil *sqlinsert.candyInsert`},
+	} {
+		ins := Insert{Table: tbl, Data: tc.data}
+		if _, _, err := ins.Build(); err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Fatalf(`expected "%s", got "%v"`, tc.expected, err)
+		}
+	}
+}
+
+func TestArgsBadRows(t *testing.T) {
+	ins := Insert{Table: tbl, Data: []interface{}{recValue, nil}}
+	if args := ins.Args(); args != nil {
+		t.Fatalf(`expected no args, got "%v"`, args)
+	}
+	if params := ins.Params(); params == `` {
+		t.Fatal(`expected params`)
+	}
+}
+
+// - GroupTypes
+
+func TestTypeGroups(t *testing.T) {
+	refund := refundInsert{Id: `r1`, Amount: 3}
+	ins := NewInsert(tbl, []interface{}{recValue, &refund, recValue}, GroupTypes(map[reflect.Type]string{
+		reflect.TypeOf(refundInsert{}): `refund`,
+	}))
+	groups, err := ins.TypeGroups()
+	if err != nil {
+		t.Fatalf(`failed at TypeGroups: %s`, err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf(`expected 2 groups, got %d`, len(groups))
+	}
+	UseTokenType = QuestionMarkTokenType
+	for i, expected := range []string{
+		`INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES (?,?,?,?,?,?,?),(?,?,?,?,?,?,?)`,
+		`INSERT INTO refund (id,amount) VALUES (?,?)`,
+	} {
+		if insertSQL := groups[i].SQL(); expected != insertSQL {
+			t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+		}
+	}
+	if _, _, err = ins.Build(); err == nil || !strings.Contains(err.Error(), `use TypeGroups`) {
+		t.Fatalf(`expected error for rows of several types, got "%v"`, err)
+	}
+}
+
+func TestInsertContextGroupTypes(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rows := []interface{}{refundInsert{`r1`, 3}, &hookedInsert{Name: `nougat`}, refundInsert{`r2`, 4}}
+	ins := NewInsert(`refund`, rows, GroupTypes(map[reflect.Type]string{reflect.TypeOf(hookedInsert{}): tbl}))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO refund (id,amount) VALUES (?,?),(?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`r1`, 3, `r2`, 4).WillReturnResult(sqlmock.NewResult(0, 2))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`id-nougat`, `NOUGAT`).WillReturnResult(sqlmock.NewResult(0, 1))
+	result, err := ins.ExecContext(context.Background(), db)
+	if err != nil {
+		t.Fatalf(`failed at ExecContext: %s`, err)
+	}
+	if result.Rows != 3 || result.Inserted != 3 || result.Skipped != 0 {
+		t.Fatalf(`expected 3 rows inserted, got %+v`, result)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
+
+func TestInsertContextGroupTypesPartial(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	rows := []interface{}{refundInsert{`r1`, 3}, &hookedInsert{Name: `nougat`}}
+	ins := NewInsert(`refund`, rows, GroupTypes(map[reflect.Type]string{reflect.TypeOf(hookedInsert{}): tbl}))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(`INSERT INTO refund (id,amount) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`r1`, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	s = regexp.QuoteMeta(`INSERT INTO candy (id,candy_name) VALUES (?,?)`)
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WithArgs(`id-nougat`, `NOUGAT`).WillReturnError(errConstraint)
+	result, err := ins.ExecContext(context.Background(), db)
+	if err != errConstraint {
+		t.Fatalf(`expected "%v", got "%v"`, errConstraint, err)
+	}
+	if result == nil || result.Rows != 1 || result.Inserted != 1 {
+		t.Fatalf(`expected 1 row inserted, got %+v`, result)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf(`unmet expectations: %s`, err)
+	}
+}
@@ -0,0 +1,65 @@
+package sqlinsert
+
+// Rows models Insert.Data for rows that have no Go struct, e.g., rows read from a CSV file.
+// Columns are the column names. Each of Values is a row with one value per column, in the same order;
+// nil is NULL and Default is the column default. Rows of no Columns or no Values, or a row whose number of values
+// is not that of Columns, is an error of Insert.Build and Insert.InsertContext.
+type Rows struct {
+	Columns []string
+	Values  [][]interface{}
+}
+
+// ragged reports whether a row does not have one value per column.
+func (rows *Rows) ragged() bool {
+	for _, values := range rows.Values {
+		if len(values) != len(rows.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// asRows returns data as *Rows if it is Rows or *Rows, or Maps, *Maps, or the maps they accept.
+func asRows(data interface{}) (*Rows, bool) {
+	switch rows := data.(type) {
+	case *Rows:
+		return rows, true
+	case Rows:
+		return &rows, true
+	case *Maps:
+		return rows.Rows(), true
+	case Maps:
+		return rows.Rows(), true
+	case map[string]interface{}:
+		return (&Maps{Values: []map[string]interface{}{rows}}).Rows(), true
+	case []map[string]interface{}:
+		return (&Maps{Values: rows}).Rows(), true
+	}
+	return nil, false
+}
+
+// resolved returns a copy of the Insert, with its options, whose maps data (see asRows) is converted to *Rows, so
+// that the methods it calls convert it once rather than each time they read the data. Other data returns the
+// Insert itself.
+func (ins *Insert) resolved() *Insert {
+	switch ins.Data.(type) {
+	case *Maps, Maps, map[string]interface{}, []map[string]interface{}:
+		rows, _ := asRows(ins.Data)
+		resolved := *ins
+		resolved.Data = rows
+		return &resolved
+	}
+	return ins
+}
+
+// Default is a value that inserts the column default: the DEFAULT keyword takes the place of its bind param, and
+// Insert.Args leaves it out.
+var Default = defaultValue{}
+
+type defaultValue struct{}
+
+// isDefault reports whether v is Default.
+func isDefault(v interface{}) bool {
+	_, ok := v.(defaultValue)
+	return ok
+}
@@ -0,0 +1,84 @@
+package sqlinsert
+
+import (
+	"reflect"
+	"testing"
+)
+
+var candyRows = Rows{
+	Columns: []string{`id`, `candy_name`},
+	Values: [][]interface{}{
+		{`a`, `Gougat`},
+		{`b`, nil},
+		{`c`, `Toffee`},
+	},
+}
+
+/* Rows */
+
+func TestSQLRows(t *testing.T) {
+	UseTokenType = OrdinalNumberTokenType
+	ins := Insert{Table: tbl, Data: candyRows}
+	expected := `INSERT INTO candy (id,candy_name) VALUES ($1,$2),($3,$4),($5,$6)`
+	insertSQL := ins.SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+}
+
+func TestArgsRows(t *testing.T) {
+	ins := Insert{Table: tbl, Data: &candyRows}
+	expected := []interface{}{`a`, `Gougat`, `b`, nil, `c`, `Toffee`}
+	args := ins.Args()
+	if !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%s", got "%s"`, expected, args)
+	}
+}
+
+func TestBatchesRows(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	ins := Insert{Table: tbl, Data: candyRows}
+	batches := ins.Batches(2)
+	if len(batches) != 2 {
+		t.Fatalf(`expected 2 batches, got %d`, len(batches))
+	}
+	expected := `INSERT INTO candy (id,candy_name) VALUES (?,?)`
+	insertSQL := batches[1].SQL()
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	expectedArgs := []interface{}{`c`, `Toffee`}
+	args := batches[1].Args()
+	if !reflect.DeepEqual(expectedArgs, args) {
+		t.Fatalf(`expected "%s", got "%s"`, expectedArgs, args)
+	}
+}
+
+func TestDebugSQLRowsDefault(t *testing.T) {
+	ins := Insert{Table: tbl, Data: Rows{Columns: []string{`id`, `ts`}, Values: [][]interface{}{{`a`, Default}}}}
+	expected := `INSERT INTO candy (id,ts) VALUES ('a',DEFAULT)`
+	debugSQL := ins.DebugSQL(PostgresDialect)
+	if expected != debugSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, debugSQL)
+	}
+}
+
+func TestBuildRaggedRows(t *testing.T) {
+	for _, rows := range []Rows{
+		{},
+		{Columns: []string{`id`}},
+		{Values: [][]interface{}{{`a`}}},
+		{Columns: []string{`id`, `candy_name`}, Values: [][]interface{}{{`a`, `Gougat`}, {`b`}}},
+	} {
+		ins := Insert{Table: tbl, Data: rows}
+		if _, _, err := ins.Build(); err == nil {
+			t.Fatalf(`expected error for %v`, rows)
+		}
+		_, _ = ins.SQL(), ins.Args()
+	}
+	ins := Insert{Table: tbl, Data: Rows{Columns: []string{`id`, `candy_name`}, Values: [][]interface{}{{`a`}}}}
+	expected := `sqlinsert: row 0 has 1 values, not one per column (2)`
+	if _, _, err := ins.Build(); err == nil || expected != err.Error() {
+		t.Fatalf(`expected "%s", got "%v"`, expected, err)
+	}
+}
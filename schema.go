@@ -0,0 +1,100 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// QueryWith models functionality needed to query table metadata with database/sql via sql.DB, sql.Tx, or sql.Conn.
+type QueryWith interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// TableColumn describes a column of an existing table as reported by the database. Extra holds the MySQL column
+// attributes that a column definition must repeat to keep them, e.g., auto_increment or a COMMENT, and, in Postgres
+// and SQL Server, identity for an identity column, or generated (Postgres) or computed (SQL Server) for a generated
+// column.
+type TableColumn struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	HasDefault bool
+	Extra      string
+}
+
+const postgresDescribeTableSQL = `SELECT a.attname, format_type(a.atttypid, a.atttypmod), ` +
+	`CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END, pg_get_expr(d.adbin, d.adrelid), ` +
+	`CASE WHEN a.attidentity <> '' THEN 'identity' WHEN a.attgenerated <> '' THEN 'generated' ELSE '' END ` +
+	`FROM pg_attribute a LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum ` +
+	`WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum`
+
+const mysqlDescribeTableSQL = `SELECT column_name, column_type, is_nullable, column_default, ` +
+	`CONCAT_WS(' ', NULLIF(TRIM(REPLACE(extra, 'DEFAULT_GENERATED', '')), ''), ` +
+	`IF(column_comment = '', NULL, CONCAT('COMMENT ', QUOTE(column_comment)))) ` +
+	`FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position`
+
+// describeTableSQL selects name, type, nullability ('YES' or 'NO'), default, and extra attributes of each column
+// of a table, in column order, with the table name as the only bind arg. The table is looked up in the current
+// database (MySQL), the search path (Postgres), the named or default schema (SQL Server), or the user's schema
+// (Oracle, by its uppercase name).
+var describeTableSQL = map[Dialect]string{
+	MySQLDialect:       mysqlDescribeTableSQL,
+	SingleStoreDialect: mysqlDescribeTableSQL,
+	PostgresDialect:    postgresDescribeTableSQL,
+	CockroachDialect:   postgresDescribeTableSQL,
+	SQLiteDialect: `SELECT name, type, CASE "notnull" WHEN 1 THEN 'NO' ELSE 'YES' END, dflt_value, '' ` +
+		`FROM pragma_table_info(?) ORDER BY cid`,
+	SQLServerDialect: `SELECT column_name, data_type + COALESCE('(' + CASE character_maximum_length ` +
+		`WHEN -1 THEN 'MAX' ELSE CAST(character_maximum_length AS VARCHAR) END + ')', ''), ` +
+		`is_nullable, column_default, CASE WHEN COLUMNPROPERTY(OBJECT_ID(QUOTENAME(table_schema) + '.' + ` +
+		`QUOTENAME(table_name)), column_name, 'IsIdentity') = 1 THEN 'identity' ` +
+		`WHEN COLUMNPROPERTY(OBJECT_ID(QUOTENAME(table_schema) + '.' + QUOTENAME(table_name)), column_name, ` +
+		`'IsComputed') = 1 THEN 'computed' ELSE '' END FROM information_schema.columns ` +
+		`WHERE table_schema = COALESCE(PARSENAME(@p1, 2), SCHEMA_NAME()) AND table_name = PARSENAME(@p1, 1) ` +
+		`ORDER BY ordinal_position`,
+	OracleDialect: `SELECT column_name, data_type || CASE WHEN char_length > 0 THEN '(' || char_length || ')' ` +
+		`WHEN data_precision IS NOT NULL THEN '(' || data_precision || ` +
+		`CASE WHEN data_scale > 0 THEN ',' || data_scale END || ')' END, ` +
+		`CASE nullable WHEN 'N' THEN 'NO' ELSE 'YES' END, data_default, NULL FROM user_tab_columns ` +
+		`WHERE table_name = UPPER(:1) ORDER BY column_id`,
+}
+
+// DescribeTable returns the columns of an existing table in column order by querying the database catalog.
+// It returns an error if the table does not exist or has no columns.
+func DescribeTable(ctx context.Context, with QueryWith, dialect Dialect, table string) ([]TableColumn, error) {
+	query, ok := describeTableSQL[dialect]
+	if !ok {
+		return nil, fmt.Errorf(`sqlinsert: unsupported dialect %d`, dialect)
+	}
+	rows, err := with.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+	var columns []TableColumn
+	for rows.Next() {
+		var (
+			column   TableColumn
+			nullable string
+			dflt     sql.NullString
+			extra    sql.NullString
+		)
+		if err = rows.Scan(&column.Name, &column.Type, &nullable, &dflt, &extra); err != nil {
+			return nil, err
+		}
+		column.Nullable = nullable == `YES`
+		column.HasDefault = dflt.Valid
+		column.Extra = extra.String
+		columns = append(columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf(`sqlinsert: table %s not found`, table)
+	}
+	return columns, nil
+}
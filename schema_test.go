@@ -0,0 +1,82 @@
+package sqlinsert
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+/* DescribeTable */
+
+func TestDescribeTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(describeTableSQL[PostgresDialect])).
+		WithArgs(tbl).
+		WillReturnRows(sqlmock.NewRows([]string{`attname`, `format_type`, `nullable`, `default`, `extra`}).
+			AddRow(`id`, `character varying(36)`, `NO`, nil, ``).
+			AddRow(`candy_name`, `text`, `YES`, nil, ``).
+			AddRow(`ts`, `timestamp with time zone`, `NO`, `now()`, ``))
+	expected := []TableColumn{
+		{Name: `id`, Type: `character varying(36)`},
+		{Name: `candy_name`, Type: `text`, Nullable: true},
+		{Name: `ts`, Type: `timestamp with time zone`, HasDefault: true},
+	}
+	columns, err := DescribeTable(context.Background(), db, PostgresDialect, tbl)
+	if err != nil {
+		t.Fatalf(`failed at DescribeTable %s`, err)
+	}
+	if !reflect.DeepEqual(expected, columns) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, columns)
+	}
+}
+
+func TestDescribeTableMySQLExtra(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(describeTableSQL[MySQLDialect])).
+		WithArgs(tbl).
+		WillReturnRows(sqlmock.NewRows([]string{`column_name`, `column_type`, `is_nullable`, `column_default`, `extra`}).
+			AddRow(`id`, `bigint`, `NO`, nil, `auto_increment`).
+			AddRow(`ts`, `datetime(6)`, `NO`, `CURRENT_TIMESTAMP(6)`, `on update CURRENT_TIMESTAMP(6)`))
+	expected := []TableColumn{
+		{Name: `id`, Type: `bigint`, Extra: `auto_increment`},
+		{Name: `ts`, Type: `datetime(6)`, HasDefault: true, Extra: `on update CURRENT_TIMESTAMP(6)`},
+	}
+	columns, err := DescribeTable(context.Background(), db, MySQLDialect, tbl)
+	if err != nil {
+		t.Fatalf(`failed at DescribeTable %s`, err)
+	}
+	if !reflect.DeepEqual(expected, columns) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, columns)
+	}
+}
+
+func TestDescribeTableNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(describeTableSQL[MySQLDialect])).
+		WithArgs(tbl).
+		WillReturnRows(sqlmock.NewRows([]string{`column_name`, `column_type`, `is_nullable`, `column_default`, `extra`}))
+	if _, err = DescribeTable(context.Background(), db, MySQLDialect, tbl); err == nil {
+		t.Fatal(`expected error for missing table`)
+	}
+}
+
+func TestDescribeTableUnsupportedDialect(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	if _, err = DescribeTable(context.Background(), db, Dialect(0), tbl); err == nil {
+		t.Fatal(`expected error for unsupported dialect`)
+	}
+}
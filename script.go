@@ -0,0 +1,80 @@
+package sqlinsert
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Script models a SQL script of INSERT statements with literal values, e.g., seed data for a local database or
+// test fixtures, written from the same structs that are inserted at runtime.
+// Dialect determines the literal escaping and the transaction statements. BatchSize, if greater than 0, limits
+// the rows per INSERT statement. Transaction wraps the statements in BEGIN and COMMIT. Set is a preamble of SET
+// statements, e.g., []string{`NAMES utf8mb4`} for `SET NAMES utf8mb4;`.
+type Script struct {
+	Dialect     Dialect
+	BatchSize   int
+	Transaction bool
+	Set         []string
+}
+
+// beginCommit are the statements that begin and commit a transaction, per dialect. Oracle begins implicitly.
+var beginCommit = map[Dialect][2]string{
+	MySQLDialect:       {`START TRANSACTION`, `COMMIT`},
+	SingleStoreDialect: {`START TRANSACTION`, `COMMIT`},
+	PostgresDialect:    {`BEGIN`, `COMMIT`},
+	CockroachDialect:   {`BEGIN`, `COMMIT`},
+	SQLiteDialect:      {`BEGIN TRANSACTION`, `COMMIT`},
+	SQLServerDialect:   {`BEGIN TRANSACTION`, `COMMIT TRANSACTION`},
+	OracleDialect:      {``, `COMMIT`},
+}
+
+// Write writes the script of the inserts to w, one statement per line, each terminated by a semicolon. Each insert
+// is written as its TypeGroups, each of them in batches, and each batch as its DefaultGroups in the dialect; in
+// Oracle, a batch of several rows is an INSERT ALL.
+// It returns a *ConvertError, having written the script up to the failing statement, if a bind arg fails to
+// convert or has no SQL literal.
+func (s Script) Write(w io.Writer, inserts ...Insert) error {
+	tx, ok := beginCommit[s.Dialect]
+	if !ok {
+		return fmt.Errorf(`sqlinsert: unsupported dialect %d`, s.Dialect)
+	}
+	for _, set := range s.Set {
+		if _, err := fmt.Fprintf(w, "SET %s;\n", set); err != nil {
+			return err
+		}
+	}
+	if s.Transaction && tx[0] != `` {
+		if _, err := fmt.Fprintf(w, "%s;\n", tx[0]); err != nil {
+			return err
+		}
+	}
+	for i := range inserts {
+		typeGroups, err := inserts[i].TypeGroups()
+		if err != nil {
+			return err
+		}
+		for _, typeGroup := range typeGroups {
+			for _, batch := range typeGroup.Batches(s.BatchSize) {
+				for _, group := range batch.DefaultGroups(s.Dialect) {
+					insertSQL, err := group.literalSQL(s.Dialect, true)
+					if err != nil {
+						return err
+					}
+					if !strings.HasSuffix(insertSQL, `;`) {
+						insertSQL += `;`
+					}
+					if _, err = fmt.Fprintln(w, insertSQL); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	if s.Transaction {
+		if _, err := fmt.Fprintf(w, "%s;\n", tx[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
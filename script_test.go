@@ -0,0 +1,88 @@
+package sqlinsert
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+/* Script */
+
+func TestScriptWrite(t *testing.T) {
+	type seed struct {
+		Id   int    `col:"id"`
+		Name string `col:"name"`
+	}
+	script := Script{
+		Dialect:     PostgresDialect,
+		BatchSize:   2,
+		Transaction: true,
+		Set:         []string{`client_encoding = 'UTF8'`},
+	}
+	var buf bytes.Buffer
+	err := script.Write(&buf,
+		Insert{Table: `seeds`, Data: []seed{{1, `one`}, {2, `two's`}, {3, `three`}}},
+		Insert{Table: tbl, Data: recPointer},
+	)
+	if err != nil {
+		t.Fatalf(`failed at Write %s`, err)
+	}
+	expected := "SET client_encoding = 'UTF8';\n" +
+		"BEGIN;\n" +
+		"INSERT INTO seeds (id,name) VALUES (1,'one'),(2,'two''s');\n" +
+		"INSERT INTO seeds (id,name) VALUES (3,'three');\n" +
+		"INSERT INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES " +
+		"('c0600afd-78a7-4a1a-87c5-1bc48cafd14e','Gougat','Package','tastes like gopher feed','Gouggle',1.1618," +
+		"'0001-01-01 00:00:00+00:00');\n" +
+		"COMMIT;\n"
+	if expected != buf.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, buf.String())
+	}
+}
+
+func TestScriptWriteOracleTransaction(t *testing.T) {
+	var buf bytes.Buffer
+	err := Script{Dialect: OracleDialect, BatchSize: 3, Transaction: true}.Write(&buf,
+		Insert{Table: tbl, Data: fiveRecsValues})
+	if err != nil {
+		t.Fatalf(`failed at Write %s`, err)
+	}
+	into := `INTO candy (id,candy_name,form_factor,description,manufacturer,weight_grams,ts) VALUES `
+	expected := "INSERT ALL " +
+		into + "('a','a','a','a','a',1.1,TIMESTAMP '0001-01-01 00:00:00 +00:00') " +
+		into + "('b','b','b','b','b',2.1,TIMESTAMP '0001-01-01 00:00:00 +00:00') " +
+		into + "('c','c','c','c','c',3.1,TIMESTAMP '0001-01-01 00:00:00 +00:00') SELECT 1 FROM DUAL;\n" +
+		"INSERT ALL " +
+		into + "('d','d','d','d','d',4.1,TIMESTAMP '0001-01-01 00:00:00 +00:00') " +
+		into + "('e','e','e','e','e',5.1,TIMESTAMP '0001-01-01 00:00:00 +00:00') SELECT 1 FROM DUAL;\n" +
+		"COMMIT;\n"
+	if expected != buf.String() {
+		t.Fatalf(`expected "%s", got "%s"`, expected, buf.String())
+	}
+}
+
+func TestScriptWriteConvertError(t *testing.T) {
+	var buf bytes.Buffer
+	err := Script{Dialect: PostgresDialect, BatchSize: 1}.Write(&buf,
+		Insert{Table: tbl, Data: []*priceInsert{{`a`, money{1}, nil}, {``, money{2}, nil}}})
+	var convertErr *ConvertError
+	if !errors.As(err, &convertErr) {
+		t.Fatalf(`expected ConvertError, got %v`, err)
+	}
+	if convertErr.Row != 1 || convertErr.Column != `sku` || convertErr.Field != `Sku` {
+		t.Fatalf(`unexpected ConvertError %+v`, convertErr)
+	}
+}
+
+func TestScriptWriteUnsupportedType(t *testing.T) {
+	type unsupported struct {
+		Tags []string  `col:"tags"`
+		Ts   time.Time `col:"ts"`
+	}
+	var buf bytes.Buffer
+	err := Script{Dialect: MySQLDialect}.Write(&buf, Insert{Table: tbl, Data: unsupported{}})
+	if err == nil {
+		t.Fatal(`expected error for unsupported Go type`)
+	}
+}
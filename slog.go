@@ -0,0 +1,44 @@
+//go:build go1.21
+
+package sqlinsert
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogObserver is an Observer that logs every insert to a log/slog Logger when it finishes: at Level if it
+// succeeds, at slog.LevelError if it fails. Bind args are logged only if ObserveArgs is true.
+type SlogObserver struct {
+	Logger *slog.Logger
+	Level  slog.Level
+}
+
+// OnStart returns ctx unchanged. The start of an insert is logged with its finish.
+func (o SlogObserver) OnStart(ctx context.Context, _ StartEvent) context.Context {
+	return ctx
+}
+
+// OnFinish logs the event.
+func (o SlogObserver) OnFinish(ctx context.Context, event FinishEvent) {
+	logger := o.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	level := o.Level
+	attrs := []slog.Attr{
+		slog.String(`query`, event.Query),
+		slog.Int(`rows`, event.Rows),
+		slog.Int(`num_args`, event.NumArgs),
+		slog.Duration(`duration`, event.Duration),
+		slog.Int64(`rows_affected`, event.RowsAffected),
+	}
+	if event.Args != nil {
+		attrs = append(attrs, slog.Any(`args`, event.Args))
+	}
+	if event.Err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any(`error`, event.Err))
+	}
+	logger.LogAttrs(ctx, level, `sqlinsert: insert`, attrs...)
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+
+package sqlinsert
+
+import (
+	"bytes"
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+/* SlogObserver */
+
+func TestSlogObserver(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	var buf bytes.Buffer
+	observer := SlogObserver{Logger: slog.New(slog.NewTextHandler(&buf, nil)), Level: slog.LevelInfo}
+	ins := NewInsert(tbl, recPointer, WithObserver(observer))
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	s := regexp.QuoteMeta(ins.SQL())
+	mock.ExpectPrepare(s)
+	mock.ExpectExec(s).WillReturnResult(sqlmock.NewResult(1, 1))
+	_, err = ins.InsertContext(context.Background(), db)
+	if err != nil {
+		t.Fatalf(`failed at InsertContext, could not execute SQL statement %s`, err)
+	}
+	logged := buf.String()
+	for _, expected := range []string{`level=INFO`, `msg="sqlinsert: insert"`, `rows=1`, `num_args=7`,
+		`rows_affected=1`} {
+		if !strings.Contains(logged, expected) {
+			t.Fatalf(`expected "%s" in "%s"`, expected, logged)
+		}
+	}
+	if strings.Contains(logged, `Gougat`) {
+		t.Fatalf(`expected no args in "%s"`, logged)
+	}
+}
@@ -0,0 +1,170 @@
+package sqlinsert
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// StmtCache keeps the prepared statements of a *sql.DB keyed by their SQL, so that inserts of the same shape (row type,
+// row count, and token type, which together determine Insert.SQL) reuse one prepared statement. Pass it to
+// Insert.Insert or Insert.InsertContext in place of the *sql.DB, which then return a nil statement, since the statement
+// belongs to the cache. When the cache is full, the least recently used statement is closed. A statement whose
+// execution fails with driver.ErrBadConn is dropped and prepared anew on next use. StmtCache is safe for concurrent
+// use.
+type StmtCache struct {
+	db    *sql.DB
+	size  int
+	mu    sync.Mutex
+	lru   *list.List // *cachedStmt, most recently used first
+	stmts map[string]*list.Element
+}
+
+// cachedStmt is a prepared statement in a StmtCache. A statement dropped from the cache while in use is closed
+// when its last user releases it.
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	users   int
+	dropped bool
+}
+
+// NewStmtCache returns a StmtCache of at most size prepared statements on db.
+func NewStmtCache(db *sql.DB, size int) *StmtCache {
+	if size < 1 {
+		size = 1
+	}
+	return &StmtCache{db: db, size: size, lru: list.New(), stmts: make(map[string]*list.Element)}
+}
+
+// Prepare prepares query on the *sql.DB, as PrepareContext does.
+func (c *StmtCache) Prepare(query string) (*sql.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext prepares query on the *sql.DB with ctx. The statement is not cached, for the cache could close it
+// while in use by the caller when it is full: the statement belongs to the caller, who closes it. Insert.Insert and
+// Insert.InsertContext use the cached statements.
+func (c *StmtCache) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.db.PrepareContext(ctx, query)
+}
+
+// Exec executes query on the *sql.DB without preparing it.
+func (c *StmtCache) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.db.Exec(query, args...)
+}
+
+// ExecContext executes query on the *sql.DB with ctx without preparing it.
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(ctx, query, args...)
+}
+
+// Len returns the number of cached statements.
+func (c *StmtCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Close closes every cached statement and empties the cache.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var err error
+	for e := c.lru.Front(); e != nil; e = c.lru.Front() {
+		if closeErr := c.drop(e); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// acquire returns the cached statement for query, preparing and caching it if needed, and marks it in use.
+func (c *StmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if e, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(e)
+		cached := e.Value.(*cachedStmt)
+		cached.users++
+		c.mu.Unlock()
+		return cached, nil
+	}
+	c.mu.Unlock()
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.stmts[query]; ok { // Prepared concurrently by another caller, use theirs
+		_ = stmt.Close()
+		c.lru.MoveToFront(e)
+		cached := e.Value.(*cachedStmt)
+		cached.users++
+		return cached, nil
+	}
+	cached := &cachedStmt{query: query, stmt: stmt, users: 1}
+	c.stmts[query] = c.lru.PushFront(cached)
+	for c.lru.Len() > c.size {
+		_ = c.drop(c.lru.Back())
+	}
+	return cached, nil
+}
+
+// release marks the statement no longer in use by one caller, whose use ended with err, and drops it from the
+// cache if err is driver.ErrBadConn.
+func (c *StmtCache) release(cached *cachedStmt, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached.users--
+	if errors.Is(err, driver.ErrBadConn) && !cached.dropped {
+		_ = c.drop(c.stmts[cached.query])
+	} else if cached.dropped && cached.users == 0 {
+		_ = cached.stmt.Close()
+	}
+}
+
+// drop removes the statement from the cache, closing it unless it is in use. c.mu must be held.
+func (c *StmtCache) drop(e *list.Element) error {
+	cached := e.Value.(*cachedStmt)
+	c.lru.Remove(e)
+	delete(c.stmts, cached.query)
+	cached.dropped = true
+	if cached.users == 0 {
+		return cached.stmt.Close()
+	}
+	return nil
+}
+
+// prepare prepares query on with and returns the statement and a function to call with the error, if any, of
+// executing it, which closes the statement or, if with is a StmtCache, releases it to the cache.
+func prepare(ctx context.Context, with InsertWith, query string) (*sql.Stmt, func(error), error) {
+	if c, ok := with.(*StmtCache); ok {
+		cached, err := c.acquire(ctx, query)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cached.stmt, func(err error) {
+			c.release(cached, err)
+		}, nil
+	}
+	stmt, err := with.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stmt, func(error) {
+		_ = stmt.Close()
+	}, nil
+}
+
+// callerStmt returns the statement of an insert on with for the caller: nil if with is a StmtCache, which owns the
+// statement and may close it when it is full, or else stmt.
+func callerStmt(with InsertWith, stmt *sql.Stmt) *sql.Stmt {
+	if _, ok := with.(*StmtCache); ok {
+		return nil
+	}
+	return stmt
+}
@@ -0,0 +1,135 @@
+package sqlinsert
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"testing"
+)
+
+/* StmtCache */
+
+// TestStmtCacheReuse tests that inserts of the same shape prepare one statement
+func TestStmtCacheReuse(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	cache := NewStmtCache(db, 10)
+	ins := Insert{Table: tbl, Data: recValue}
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(ins.SQL()))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(2, 1))
+	prep.WillBeClosed()
+	stmt, err := ins.Insert(cache)
+	if err != nil {
+		t.Fatalf(`failed at Insert, could not execute SQL statement %s`, err)
+	}
+	if stmt != nil {
+		t.Fatal(`expected no statement from the cache`)
+	}
+	ins = Insert{Table: tbl, Data: recPointer}
+	if _, err = ins.InsertContext(context.Background(), cache); err != nil {
+		t.Fatalf(`failed at InsertContext, could not execute SQL statement %s`, err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf(`expected 1 cached statement, got %d`, cache.Len())
+	}
+	if err = cache.Close(); err != nil {
+		t.Fatalf(`failed at Close %s`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStmtCacheEviction tests that the least recently used statement is closed when the cache is full
+func TestStmtCacheEviction(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	cache := NewStmtCache(db, 1)
+	one := Insert{Table: tbl, Data: recValue}
+	five := Insert{Table: tbl, Data: fiveRecsValues}
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(one.SQL()))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+	prep.WillBeClosed()
+	mock.ExpectPrepare(regexp.QuoteMeta(five.SQL())).
+		ExpectExec().WillReturnResult(sqlmock.NewResult(5, 5))
+	if _, err = one.Insert(cache); err != nil {
+		t.Fatalf(`failed at Insert, could not execute SQL statement %s`, err)
+	}
+	if _, err = five.Insert(cache); err != nil {
+		t.Fatalf(`failed at Insert, could not execute SQL statement %s`, err)
+	}
+	if cache.Len() != 1 {
+		t.Fatalf(`expected 1 cached statement, got %d`, cache.Len())
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStmtCacheBadConn tests that a statement is dropped when its execution fails with driver.ErrBadConn
+func TestStmtCacheBadConn(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	cache := NewStmtCache(db, 10)
+	ins := Insert{Table: tbl, Data: recValue}
+	query := ins.SQL()
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).WillBeClosed()
+	cached, err := cache.acquire(context.Background(), query)
+	if err != nil {
+		t.Fatalf(`failed at acquire %s`, err)
+	}
+	cache.release(cached, driver.ErrBadConn)
+	if cache.Len() != 0 {
+		t.Fatalf(`expected no cached statements, got %d`, cache.Len())
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestStmtCachePrepare tests that a statement prepared by the caller is not cached, so is not closed by eviction
+func TestStmtCachePrepare(t *testing.T) {
+	UseTokenType = QuestionMarkTokenType
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf(`failed to construct SQL mock %s`, err)
+	}
+	cache := NewStmtCache(db, 1)
+	one := Insert{Table: tbl, Data: recValue}
+	five := Insert{Table: tbl, Data: fiveRecsValues}
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(one.SQL()))
+	mock.ExpectPrepare(regexp.QuoteMeta(five.SQL())).
+		ExpectExec().WillReturnResult(sqlmock.NewResult(5, 5))
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(1, 1))
+	prep.WillBeClosed()
+	stmt, err := cache.Prepare(one.SQL())
+	if err != nil {
+		t.Fatalf(`failed at Prepare %s`, err)
+	}
+	if cache.Len() != 0 {
+		t.Fatalf(`expected no cached statements, got %d`, cache.Len())
+	}
+	if _, err = five.Insert(cache); err != nil {
+		t.Fatalf(`failed at Insert, could not execute SQL statement %s`, err)
+	}
+	if _, err = stmt.Exec(one.Args()...); err != nil {
+		t.Fatalf(`failed at Exec, could not execute SQL statement %s`, err)
+	}
+	if err = stmt.Close(); err != nil {
+		t.Fatalf(`failed at Close %s`, err)
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
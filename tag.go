@@ -0,0 +1,83 @@
+package sqlinsert
+
+import (
+	"reflect"
+	"strings"
+)
+
+// columnTag is a parsed struct tag: the column name followed by comma-separated options, e.g.,
+// `col:"id,pk,size=36"`. Options are either flags (`pk`) or key-value pairs (`size=36`).
+// Commas inside parentheses or single quotes do not split options, so `type=DECIMAL(9,3)` and
+// `default='a,b'` are read whole.
+type columnTag struct {
+	name string
+	opts map[string]string
+}
+
+// parseTag parses the struct tag specified by UseStructTag on the given field.
+func parseTag(field reflect.StructField) columnTag {
+	parts := splitTag(field.Tag.Get(UseStructTag))
+	tag := columnTag{name: parts[0], opts: make(map[string]string, len(parts)-1)}
+	for _, part := range parts[1:] {
+		if i := strings.Index(part, `=`); i >= 0 {
+			tag.opts[part[:i]] = part[i+1:]
+		} else if part != `` {
+			tag.opts[part] = ``
+		}
+	}
+	return tag
+}
+
+// has reports whether the option is present.
+func (tag columnTag) has(opt string) bool {
+	_, ok := tag.opts[opt]
+	return ok
+}
+
+// castOpts are the options whose value, if any, is the type that bind params are cast to in Postgres.
+var castOpts = []string{`json`, `array`, `composite`}
+
+// cast returns the type cast that follows the bind param of a column with the tag in the dialect: in Postgres,
+// `::jsonb` for `col:"attributes,json=jsonb"`, `::text[]` for `col:"tags,array=text[]"`, and so on. There is none
+// otherwise.
+func (tag columnTag) cast(dialect Dialect) string {
+	if !dialect.postgresLike() {
+		return ``
+	}
+	for _, opt := range castOpts {
+		if castType := tag.opts[opt]; castType != `` {
+			return `::` + castType
+		}
+	}
+	return ``
+}
+
+// splitTag splits a tag value on commas that are not inside parentheses or single quotes.
+func splitTag(s string) []string {
+	var (
+		parts  []string
+		depth  int
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			quoted = !quoted
+		case '(':
+			if !quoted {
+				depth++
+			}
+		case ')':
+			if !quoted && depth > 0 {
+				depth--
+			}
+		case ',':
+			if !quoted && depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -1,6 +1,9 @@
 package sqlinsert
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 var valuesTokenTypes = []TokenType{
 	QuestionMarkTokenType,
@@ -56,3 +59,15 @@ var fiveRecsPointers = []*candyInsert{
 	{`d`, `d`, `d`, `d`, `d`, 4.1, time.Time{}},
 	{`e`, `e`, `e`, `e`, `e`, 5.1, time.Time{}},
 }
+
+type candyTable struct {
+	Id          string         `col:"id,pk,size=36"`
+	Name        string         `col:"candy_name,unique"`
+	FormFactor  *string        `col:"form_factor"`
+	Description sql.NullString `col:"description"`
+	Mfr         string         `col:"manufacturer,null"`
+	Weight      float64        `col:"weight_grams,type=DECIMAL(9,3)"`
+	Stock       int32          `col:"stock,default=0"`
+	Image       []byte         `col:"image"`
+	Timestamp   time.Time      `col:"ts,default=CURRENT_TIMESTAMP"`
+}
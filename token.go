@@ -7,6 +7,7 @@ import (
 )
 
 // UseStructTag specifies the struct tag key for the column name. Default is `col`.
+// Options may follow the column name, e.g., `col:"id,pk,size=36"`; most are for CreateTableSQL only.
 var UseStructTag = `col`
 
 // TokenType represents a type of token in a SQL INSERT statement, whether column or value expression.
@@ -37,33 +38,99 @@ const (
 	// ColonTokenType uses : followed by the column name from the struct tag specified by UseStructTag.
 	// :foo, :bar, ... :baz -- Oracle
 	ColonTokenType TokenType = 4
+
+	// AtPOrdinalTokenType uses @p plus the value of an ordered sequence of integers starting at 1.
+	// @p1, @p2, ... @pn -- SQL Server
+	AtPOrdinalTokenType TokenType = 5
 )
 
 // UseTokenType specifies the token type to use for values. Default is the question mark (`?`).
 var UseTokenType = QuestionMarkTokenType
 
+// named reports whether the token type names its bind params after their columns, which are bound by name with
+// sql.Named (see Insert.NamedArgs).
+func (tokenType TokenType) named() bool {
+	return tokenType == AtColumnNameTokenType || tokenType == ColonTokenType
+}
+
 // Tokenize translates struct fields into the tokens of SQL column or value expressions as a comma-separated list
 // enclosed in parentheses.
 func Tokenize(recordType reflect.Type, tokenType TokenType) string {
-	var b strings.Builder
-	b.WriteString(`(`)
-	for i := 0; i < recordType.NumField(); i++ {
-		switch tokenType {
-		case ColumnNameTokenType:
-			b.WriteString(recordType.Field(i).Tag.Get(UseStructTag))
-		case QuestionMarkTokenType:
-			_, _ = fmt.Fprint(&b, `?`)
-		case AtColumnNameTokenType:
-			_, _ = fmt.Fprintf(&b, `@%s`, recordType.Field(i).Tag.Get(UseStructTag))
-		case OrdinalNumberTokenType:
-			_, _ = fmt.Fprintf(&b, `$%d`, i+1)
-		case ColonTokenType:
-			_, _ = fmt.Fprintf(&b, `:%s`, recordType.Field(i).Tag.Get(UseStructTag))
-		}
-		if i < recordType.NumField()-1 {
-			b.WriteString(`,`)
+	return tokenize(fieldColumnNames(recordType), tokenType, 0)
+}
+
+// tokenize translates column names into the tokens of SQL column or value expressions as a comma-separated list
+// enclosed in parentheses. Offset is the number of bind params before the list, which OrdinalNumberTokenType
+// continues from, so that the rows of a multi-row INSERT are numbered in sequence.
+func tokenize(columnNames []string, tokenType TokenType, offset int) string {
+	tokens, _ := tokenizeRow(columnNames, nil, nil, tokenType, offset, 0)
+	return tokens
+}
+
+// tokenizeRow is tokenize for the values of one row: DEFAULT takes the place of the token of each Default value,
+// and the token of a column with a value expression is substituted into the expression. Row, if not 0, is the
+// number of the row in a multi-row INSERT, which suffixes the names of named bind params, e.g., @foo_2, to keep them
+// unique. It also returns the number of bind params in the list.
+func tokenizeRow(columnNames []string, values []interface{}, exprs []string, tokenType TokenType, offset int,
+	row int) (string, int) {
+	tokens, numParams := rowTokens(columnNames, values, exprs, tokenType, offset, row)
+	return `(` + strings.Join(tokens, `,`) + `)`, numParams
+}
+
+// rowTokens is tokenizeRow without the list: it returns the token of each column.
+func rowTokens(columnNames []string, values []interface{}, exprs []string, tokenType TokenType, offset int,
+	row int) ([]string, int) {
+	var (
+		tokens    = make([]string, len(columnNames))
+		numParams int
+	)
+	for i, columnName := range columnNames {
+		expr := exprOf(exprs, i)
+		switch {
+		case values != nil && isDefault(values[i]):
+			tokens[i] = `DEFAULT`
+		case !takesArg(expr):
+			tokens[i] = expr
+		default:
+			numParams++
+			tokens[i] = substitute(expr, paramToken(paramName(columnName, row), tokenType, offset+numParams))
 		}
 	}
-	b.WriteString(`)`)
-	return b.String()
+	return tokens, numParams
+}
+
+// paramName returns the name of the bind param of a column in the given row of a multi-row INSERT (see
+// tokenizeRow).
+func paramName(columnName string, row int) string {
+	if row == 0 {
+		return columnName
+	}
+	return fmt.Sprintf(`%s_%d`, columnName, row)
+}
+
+// paramToken returns the token of the named bind param, which is the given ordinal in the statement.
+func paramToken(name string, tokenType TokenType, ordinal int) string {
+	switch tokenType {
+	case QuestionMarkTokenType:
+		return `?`
+	case AtColumnNameTokenType:
+		return `@` + name
+	case OrdinalNumberTokenType:
+		return fmt.Sprintf(`$%d`, ordinal)
+	case ColonTokenType:
+		return `:` + name
+	case AtPOrdinalTokenType:
+		return fmt.Sprintf(`@p%d`, ordinal)
+	}
+	return name
+}
+
+// fieldColumnNames returns the column names of the fields of recordType from the struct tag specified by
+// UseStructTag, in field order.
+func fieldColumnNames(recordType reflect.Type) []string {
+	columnNames := make([]string, recordType.NumField())
+	for i := range columnNames {
+		columnNames[i] = parseTag(recordType.Field(i)).name
+	}
+	return columnNames
 }
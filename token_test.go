@@ -1,12 +1,13 @@
 package sqlinsert
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 )
 
 func TestTokenizeColumnNameTokenType(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `(id,candy_name,form_factor,description,manufacturer,weight_grams,ts)`
 	columnNames := Tokenize(reflect.TypeOf(ins.Data), ColumnNameTokenType)
 	if expected != columnNames {
@@ -15,7 +16,7 @@ func TestTokenizeColumnNameTokenType(t *testing.T) {
 }
 
 func TestTokenizeQuestionMarkTokenType(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `(?,?,?,?,?,?,?)`
 	bindParams := Tokenize(reflect.TypeOf(ins.Data), QuestionMarkTokenType)
 	if expected != bindParams {
@@ -24,7 +25,7 @@ func TestTokenizeQuestionMarkTokenType(t *testing.T) {
 }
 
 func TestTokenizeAtColumnNameTokenType(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `(@id,@candy_name,@form_factor,@description,@manufacturer,@weight_grams,@ts)`
 	bindParams := Tokenize(reflect.TypeOf(ins.Data), AtColumnNameTokenType)
 	if expected != bindParams {
@@ -33,7 +34,7 @@ func TestTokenizeAtColumnNameTokenType(t *testing.T) {
 }
 
 func TestTokenizeOrdinalNumberTokenType(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `($1,$2,$3,$4,$5,$6,$7)`
 	bindParams := Tokenize(reflect.TypeOf(ins.Data), OrdinalNumberTokenType)
 	if expected != bindParams {
@@ -41,11 +42,81 @@ func TestTokenizeOrdinalNumberTokenType(t *testing.T) {
 	}
 }
 
+func TestTokenizeAtPOrdinalTokenType(t *testing.T) {
+	ins := Insert{Table: tbl, Data: recValue}
+	expected := `(@p1,@p2,@p3,@p4,@p5,@p6,@p7)`
+	bindParams := Tokenize(reflect.TypeOf(ins.Data), AtPOrdinalTokenType)
+	if expected != bindParams {
+		t.Fatalf(`expected "%s", got "%s"`, expected, bindParams)
+	}
+}
+
 func TestTokenizeColonTokenType(t *testing.T) {
-	ins := Insert{tbl, recValue}
+	ins := Insert{Table: tbl, Data: recValue}
 	expected := `(:id,:candy_name,:form_factor,:description,:manufacturer,:weight_grams,:ts)`
 	bindParams := Tokenize(reflect.TypeOf(ins.Data), ColonTokenType)
 	if expected != bindParams {
 		t.Fatalf(`expected "%s", got "%s"`, expected, bindParams)
 	}
 }
+
+func TestTokenizeTagOptions(t *testing.T) {
+	expected := `(id,candy_name,form_factor,description,manufacturer,weight_grams,stock,image,ts)`
+	columnNames := Tokenize(reflect.TypeOf(candyTable{}), ColumnNameTokenType)
+	if expected != columnNames {
+		t.Fatalf(`expected "%s", got "%s"`, expected, columnNames)
+	}
+}
+
+func TestTokenizeTagOptionsAtColumnName(t *testing.T) {
+	expected := `(@id,@candy_name,@form_factor,@description,@manufacturer,@weight_grams,@stock,@image,@ts)`
+	bindParams := Tokenize(reflect.TypeOf(candyTable{}), AtColumnNameTokenType)
+	if expected != bindParams {
+		t.Fatalf(`expected "%s", got "%s"`, expected, bindParams)
+	}
+}
+
+func TestParamsMultiRowNamed(t *testing.T) {
+	defer func(tokenType TokenType) { UseTokenType = tokenType }(UseTokenType)
+	ins := Insert{Table: tbl, Data: Rows{Columns: []string{`id`, `n`}, Values: [][]interface{}{{`a`, 1}, {`b`, 2}}}}
+	for tokenType, expected := range map[TokenType]string{
+		AtColumnNameTokenType: `(@id_1,@n_1),(@id_2,@n_2)`,
+		ColonTokenType:        `(:id_1,:n_1),(:id_2,:n_2)`,
+		AtPOrdinalTokenType:   `(@p1,@p2),(@p3,@p4)`,
+	} {
+		UseTokenType = tokenType
+		if params := ins.Params(); expected != params {
+			t.Fatalf(`expected "%s", got "%s"`, expected, params)
+		}
+	}
+}
+
+func TestNamedArgs(t *testing.T) {
+	ins := Insert{Table: tbl, Data: Rows{Columns: []string{`id`, `n`}, Values: [][]interface{}{{`a`, 1}, {`b`, Default}}}}
+	expected := []interface{}{sql.Named(`id_1`, `a`), sql.Named(`n_1`, 1), sql.Named(`id_2`, `b`)}
+	if args := ins.NamedArgs(); !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+	ins.Data = Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}}}
+	expected = []interface{}{sql.Named(`id`, `a`)}
+	if args := ins.NamedArgs(); !reflect.DeepEqual(expected, args) {
+		t.Fatalf(`expected "%v", got "%v"`, expected, args)
+	}
+}
+
+func TestBuildNamedArgs(t *testing.T) {
+	defer func(tokenType TokenType) { UseTokenType = tokenType }(UseTokenType)
+	UseTokenType = AtColumnNameTokenType
+	ins := Insert{Table: tbl, Data: Rows{Columns: []string{`id`}, Values: [][]interface{}{{`a`}}}}
+	insertSQL, args, err := ins.Build()
+	if err != nil {
+		t.Fatalf(`failed at Build: %s`, err)
+	}
+	expected := `INSERT INTO candy (id) VALUES (@id)`
+	if expected != insertSQL {
+		t.Fatalf(`expected "%s", got "%s"`, expected, insertSQL)
+	}
+	if !reflect.DeepEqual([]interface{}{sql.Named(`id`, `a`)}, args) {
+		t.Fatalf(`expected named arg id, got "%v"`, args)
+	}
+}